@@ -1,6 +1,8 @@
 package main
 
 import (
+	"academic-api/internal/domain"
+	"academic-api/internal/domain/school"
 	"academic-api/internal/handler"
 	"academic-api/internal/middleware"
 	"academic-api/internal/service"
@@ -9,6 +11,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -22,12 +25,17 @@ import (
 const (
 	// Server configuration
 	defaultPort            = "8080"
+	defaultApiVersion      = "v1"
 	defaultEnv             = "development"
 	defaultLogLevel        = "debug"
 	defaultShutdownTimeout = 30 * time.Second
 	defaultReadTimeout     = 15 * time.Second
 	defaultWriteTimeout    = 15 * time.Second
 	defaultIdleTimeout     = 60 * time.Second
+
+	// Token lifetimes
+	defaultJwtExpiration        = 24 * time.Hour
+	defaultJwtRefreshExpiration = 7 * 24 * time.Hour
 )
 
 func getEnv(envVar string, def string) string {
@@ -38,6 +46,33 @@ func getEnv(envVar string, def string) string {
 	return val
 }
 
+// getEnvHours reads a whole number of hours, falling back to def when unset or invalid
+func getEnvHours(envVar string, def time.Duration) time.Duration {
+	hours, err := strconv.Atoi(os.Getenv(envVar))
+	if err != nil || hours <= 0 {
+		return def
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// getEnvInt reads a positive integer, falling back to def when unset or invalid
+func getEnvInt(envVar string, def int) int {
+	val, err := strconv.Atoi(os.Getenv(envVar))
+	if err != nil || val <= 0 {
+		return def
+	}
+	return val
+}
+
+// getEnvBool reads a boolean, falling back to def when unset or invalid
+func getEnvBool(envVar string, def bool) bool {
+	val, err := strconv.ParseBool(os.Getenv(envVar))
+	if err != nil {
+		return def
+	}
+	return val
+}
+
 func init() {
 	err := godotenv.Load()
 	if err != nil {
@@ -80,19 +115,82 @@ func main() {
 	// TODO: introduce logging for SQL ops here
 	dbSess := dbConn.NewSession(nil)
 
+	// Triggers on the school table write to the FTS5 school search index
+	err = school.CheckSearchIndex(dbSess)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to check the school search index.")
+	}
+
+	// Bound the page size of every query
+	domain.MaxPageSize = getEnvInt("MAX_PAGE_SIZE", domain.DefaultMaxPageSize)
+	domain.DefaultPageSize = min(getEnvInt("DEFAULT_PAGE_SIZE", domain.StandardPageSize), domain.MaxPageSize)
+
+	// Init district service and handler
+	districtService := service.NewDistrictService(dbSess)
+	districtHandler := handler.NewDistrictHandler(districtService)
+
+	// Init raw data service and handler
+	rawDataService := service.NewRawDataService(dbSess)
+	rawDataHandler := handler.NewRawDataHandler(rawDataService)
+
 	// Init school service and handler
 	schoolService := service.NewSchoolService(dbSess)
 	schoolHander := handler.NewSchoolHandler(schoolService)
 
 	// Init school report service and handler
-	schoolReportService := service.NewSchoolReportService(dbSess)
+	schoolReportService := service.NewSchoolReportService(dbSess, service.BulkConfig{
+		Enabled:   getEnvBool("FEATURE_BULK_IMPORT", false),
+		ChunkSize: getEnvInt("BULK_CHUNK_SIZE", service.DefaultBulkChunkSize),
+	})
 	schoolReportHandler := handler.NewSchoolReportHandler(schoolReportService)
 
+	// Init purge service and admin handler
+	purgeService := service.NewPurgeService(dbSess)
+	adminHandler := handler.NewAdminHandler(purgeService)
+
 	// Init auth middleware
-	jwtMiddleware := middleware.NewJwtMiddleware(middleware.AuthHeaderName, middleware.BearerPrefix)
+	revocationService := service.NewRevocationService(dbSess)
+	jwtConfig := middleware.JwtConfig{
+		Secret:      []byte(getEnv("JWT_SECRET", "")),
+		Issuer:      getEnv("JWT_ISSUER", ""),
+		Audience:    getEnv("JWT_AUDIENCE", ""),
+		Revocations: revocationService,
+	}
+	if publicKeyPath := getEnv("JWT_PUBLIC_KEY_PATH", ""); publicKeyPath != "" {
+		jwtConfig.PublicKey, err = middleware.LoadRsaPublicKey(publicKeyPath)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to load JWT public key.")
+		}
+	}
+	jwtMiddleware := middleware.NewJwtMiddleware(middleware.AuthHeaderName, middleware.BearerPrefix, jwtConfig)
+
+	apiKeys, err := middleware.ParseApiKeys(getEnv("API_KEYS", ""))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to parse API keys.")
+	}
+	apiKeyScopes, err := middleware.ParseApiKeyScopes(getEnv("API_KEY_SCOPES", ""))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to parse API key scopes.")
+	}
+	apiKeyMiddleware := middleware.NewApiKeyMiddleware(middleware.ApiKeyHeaderName, middleware.AuthHeaderName, middleware.ApiKeyPrefix, apiKeys, apiKeyScopes)
+
+	// Accept either a JWT or an API key
+	authMiddleware := middleware.NewChainAuthMiddleware(jwtMiddleware, apiKeyMiddleware)
+
+	// Init token issuance service and handler
+	jwtIssuer := middleware.NewJwtIssuer(
+		jwtConfig.Secret,
+		jwtConfig.Issuer,
+		jwtConfig.Audience,
+		getEnvHours("JWT_EXPIRATION", defaultJwtExpiration),
+		getEnvHours("JWT_REFRESH_EXPIRATION", defaultJwtRefreshExpiration),
+	)
+	authService := service.NewAuthService(jwtIssuer, jwtMiddleware, apiKeyMiddleware, revocationService)
+	authHandler := handler.NewAuthHandler(authService)
 
 	// Init router
-	router := handler.NewRouter(schoolHander, schoolReportHandler, jwtMiddleware)
+	apiVersion := getEnv("API_VERSION", defaultApiVersion)
+	router := handler.NewRouter(districtHandler, rawDataHandler, schoolHander, schoolReportHandler, authHandler, adminHandler, authMiddleware, apiVersion)
 	routeHandler, err := router.GetRouteHandler()
 	if err != nil {
 		log.WithError(err).Fatal("Failed to create router.")
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"academic-api/internal/domain/school"
+	"academic-api/internal/service"
+	"flag"
+	"os"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/joho/godotenv"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultDbPath = "./data/academic_data.db"
+)
+
+func getEnv(envVar string, def string) string {
+	val := os.Getenv(envVar)
+	if val == "" {
+		val = def
+	}
+	return val
+}
+
+// Permanently deletes records soft deleted more than -older-than-days days ago
+func main() {
+	// The environment file is optional for one-off runs
+	_ = godotenv.Load()
+
+	dbPath := flag.String("db", getEnv("DB_PATH", defaultDbPath), "SQLite database file path")
+	olderThanDays := flag.Int("older-than-days", -1, "Purge records soft deleted more than this many days ago")
+	flag.Parse()
+
+	log := logrus.WithFields(logrus.Fields{
+		"service": "academic-api-purge",
+	})
+
+	if *olderThanDays < 0 {
+		flag.Usage()
+		log.Fatal("Flag -older-than-days must be a non-negative number of days.")
+	}
+
+	dbConn, err := dbr.Open("sqlite3", *dbPath, nil)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to conect to database.")
+	}
+	defer dbConn.Close()
+
+	dbSess := dbConn.NewSession(nil)
+
+	// Purging schools deletes them from the FTS5 school search index
+	err = school.CheckSearchIndex(dbSess)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to check the school search index.")
+	}
+
+	purgeService := service.NewPurgeService(dbSess)
+	result, err := purgeService.Purge(*olderThanDays)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to purge deleted records.")
+	}
+
+	log.WithFields(logrus.Fields{
+		"deleted_before": result.DeletedBefore,
+		"districts":      result.Districts,
+		"schools":        result.Schools,
+		"school_reports": result.SchoolReports,
+	}).Info("Purge completed.")
+}
@@ -1,9 +1,195 @@
-package scraper
+package main
 
-func init() {
+import (
+	"academic-api/internal/domain/school"
+	"academic-api/internal/service"
+	webreader "academic-api/internal/web_reader"
+	_ "academic-api/internal/web_reader/arkansas"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/gocraft/dbr/v2"
+	"github.com/joho/godotenv"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultDbPath       = "./data/academic_data.db"
+	defaultState        = "AR"
+	defaultAcademicYear = 2024
+)
+
+const usage = `Usage: scraper <command> [flags]
+
+Commands:
+  fetch  Download report cards and store them as raw data
+  parse  Print the school reports parsed from stored raw data
+  load   Parse stored raw data and upsert its school reports
+  run    Download report cards and load their school reports
+
+Run scraper <command> -h for the flags of a command. Supported states: %s.
+`
+
+func getEnv(envVar string, def string) string {
+	val := os.Getenv(envVar)
+	if val == "" {
+		val = def
+	}
+	return val
+}
+
+// getEnvInt reads a non-negative integer, falling back to def when unset or invalid
+func getEnvInt(envVar string, def int) int {
+	val, err := strconv.Atoi(os.Getenv(envVar))
+	if err != nil || val < 0 {
+		return def
+	}
+	return val
+}
+
+// splitList splits a comma separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
+// splitIds splits a comma separated flag value of ids
+func splitIds(value string) ([]int, error) {
+	var ids []int
+	for _, item := range splitList(value) {
+		id, err := strconv.Atoi(item)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("Invalid id %s.", item)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Collects school report cards from state publications into raw_data and
+// school_report
 func main() {
+	// The environment file is optional for one-off runs
+	_ = godotenv.Load()
+
+	log := logrus.WithFields(logrus.Fields{
+		"service": "academic-api-scraper",
+	})
+
+	if len(os.Args) < 2 {
+		fmt.Fprintf(os.Stderr, usage, strings.Join(webreader.States(), ", "))
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	dbPath := flags.String("db", getEnv("DB_PATH", defaultDbPath), "SQLite database file path")
+	state := flags.String("state", defaultState, "State code of the report cards")
+
+	var fromYear, toYear *int
+	var leaIds, dataIds *string
+	switch command {
+	case "fetch", "run":
+		year := getEnvInt("DEFAULT_ACADEMIC_YEAR", defaultAcademicYear)
+		fromYear = flags.Int("from", year, "First academic year, by its ending year")
+		toYear = flags.Int("to", 0, "Last academic year, defaults to -from")
+		leaIds = flags.String("lea", "", "Comma separated LEA ids of the schools, defaults to the schools listed by the state source")
+	case "parse", "load":
+		dataIds = flags.String("data-id", "", "Comma separated ids of the raw data")
+	default:
+		fmt.Fprintf(os.Stderr, usage, strings.Join(webreader.States(), ", "))
+		os.Exit(2)
+	}
+	flags.Parse(os.Args[2:])
+
+	dbConn, err := dbr.Open("sqlite3", *dbPath, nil)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to conect to database.")
+	}
+	defer dbConn.Close()
+	dbSess := dbConn.NewSession(nil)
+
+	// Writes to the school table go through the FTS5 school search index
+	err = school.CheckSearchIndex(dbSess)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to check the school search index.")
+	}
+
+	client := webreader.NewClient(webreader.ClientConfig{
+		Timeout:       time.Duration(getEnvInt("SCRAPER_TIMEOUT", int(webreader.DefaultTimeout/time.Second))) * time.Second,
+		RetryAttempts: getEnvInt("SCRAPER_RETRY_ATTEMPTS", webreader.DefaultRetryAttempts),
+		UserAgent:     getEnv("SCRAPER_USER_AGENT", webreader.DefaultUserAgent),
+	})
+	scraperService := service.NewScraperService(dbSess, client)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var result *service.ScrapeResult
+	switch command {
+	case "fetch", "run":
+		if *toYear == 0 {
+			*toYear = *fromYear
+		}
+		if *toYear < *fromYear {
+			flags.Usage()
+			log.Fatal("Flag -to must not be before -from.")
+		}
+		leas := splitList(*leaIds)
+
+		if command == "fetch" {
+			result, err = scraperService.Fetch(ctx, *state, *fromYear, *toYear, leas)
+		} else {
+			result, err = scraperService.Run(ctx, *state, *fromYear, *toYear, leas)
+		}
+	case "parse", "load":
+		var ids []int
+		ids, err = splitIds(*dataIds)
+		if err != nil || len(ids) == 0 {
+			flags.Usage()
+			log.Fatal("Flag -data-id must list raw data ids.")
+		}
+
+		if command == "parse" {
+			reports, err := scraperService.Parse(*state, ids)
+			if err != nil {
+				log.WithError(err).Fatal("Failed to parse raw data.")
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(reports)
+			if err != nil {
+				log.WithError(err).Fatal("Failed to write school reports.")
+			}
+			return
+		}
+		result, err = scraperService.Load(*state, ids)
+	}
+	if err != nil {
+		log.WithError(err).Fatalf("Failed to %s report cards.", command)
+	}
 
+	log.WithFields(logrus.Fields{
+		"data_ids": result.DataIds,
+		"failed":   result.Failed,
+		"reports":  result.Reports,
+	}).Infof("Scraper %s completed.", command)
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
 }
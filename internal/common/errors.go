@@ -0,0 +1,89 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ApiError classifies an error by the HTTP status and machine readable code
+// it is reported with. The kinds below carry no cause and match every error
+// of their kind with errors.Is, so handlers do not need to know which layer
+// produced an error to report it.
+type ApiError struct {
+	Status int
+	Code   string
+	Err    error
+}
+
+// Error kinds
+var (
+	ErrBadRequest     = &ApiError{Status: http.StatusBadRequest, Code: "bad_request"}
+	ErrDecode         = &ApiError{Status: http.StatusBadRequest, Code: "invalid_body"}
+	ErrUnauthorized   = &ApiError{Status: http.StatusUnauthorized, Code: "unauthorized"}
+	ErrForbidden      = &ApiError{Status: http.StatusForbidden, Code: "forbidden"}
+	ErrNotFound       = &ApiError{Status: http.StatusNotFound, Code: "not_found"}
+	ErrConflict       = &ApiError{Status: http.StatusConflict, Code: "conflict"}
+	ErrValidation     = &ApiError{Status: http.StatusUnprocessableEntity, Code: "validation_failed"}
+	ErrInternal       = &ApiError{Status: http.StatusInternalServerError, Code: "internal_error"}
+	ErrNotImplemented = &ApiError{Status: http.StatusNotImplemented, Code: "not_implemented"}
+)
+
+// Kinds reported for errors written with a status they were not classified with
+var statusKinds = []*ApiError{
+	ErrBadRequest,
+	ErrUnauthorized,
+	ErrForbidden,
+	ErrNotFound,
+	ErrConflict,
+	ErrValidation,
+	ErrInternal,
+	ErrNotImplemented,
+}
+
+// Wrap returns err classified as the kind e
+func (e *ApiError) Wrap(err error) error {
+	return &ApiError{Status: e.Status, Code: e.Code, Err: err}
+}
+
+func (e *ApiError) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Status)
+	}
+	return e.Err.Error()
+}
+
+func (e *ApiError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches e against an error kind. Errors with a cause only match
+// themselves, which errors.Is already checks.
+func (e *ApiError) Is(target error) bool {
+	kind, ok := target.(*ApiError)
+	return ok && kind.Err == nil && kind.Code == e.Code
+}
+
+// ErrorCode returns the code err is reported with when written with the
+// given HTTP status
+func ErrorCode(err error, httpStatusCode int) string {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) && apiErr.Status == httpStatusCode {
+		return apiErr.Code
+	}
+
+	for _, kind := range statusKinds {
+		if kind.Status == httpStatusCode {
+			return kind.Code
+		}
+	}
+	return ErrInternal.Code
+}
+
+// ErrorStatus returns the HTTP status of err, 500 for unclassified errors
+func ErrorStatus(err error) int {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,132 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+var errTestConflict = ErrConflict.Wrap(errors.New("Test Conflict"))
+
+type ApiErrorIsTestCase struct {
+	name           string
+	err            error
+	target         error
+	expectedResult bool
+}
+
+func TestErrors_Is(t *testing.T) {
+	testCases := []ApiErrorIsTestCase{
+		{
+			name:           "Matches own kind",
+			err:            ErrValidation.Wrap(errors.New("Test Validation")),
+			target:         ErrValidation,
+			expectedResult: true,
+		},
+		{
+			name:           "Does not match other kind",
+			err:            ErrValidation.Wrap(errors.New("Test Validation")),
+			target:         ErrNotFound,
+			expectedResult: false,
+		},
+		{
+			name:           "Matches through wrapping",
+			err:            fmt.Errorf("Failed to create: %w", errTestConflict),
+			target:         ErrConflict,
+			expectedResult: true,
+		},
+		{
+			name:           "Matches own sentinel",
+			err:            fmt.Errorf("Failed to create: %w", errTestConflict),
+			target:         errTestConflict,
+			expectedResult: true,
+		},
+		{
+			name:           "Does not match sentinel of same kind",
+			err:            ErrConflict.Wrap(errors.New("Other Conflict")),
+			target:         errTestConflict,
+			expectedResult: false,
+		},
+		{
+			name:           "Matches cause",
+			err:            ErrDecode.Wrap(errTestConflict),
+			target:         errTestConflict,
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, errors.Is(tc.err, tc.target), tc.expectedResult)
+		})
+	}
+}
+
+type WriteApiErrorTestCase struct {
+	name            string
+	errorObj        error
+	expectedStatus  int
+	expectedCode    string
+	expectedMessage string
+}
+
+func TestHttpHelper_WriteApiError(t *testing.T) {
+	testCases := []WriteApiErrorTestCase{
+		{
+			name:            "Decode error",
+			errorObj:        ErrDecode.Wrap(errors.New("unexpected EOF")),
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    "invalid_body",
+			expectedMessage: "unexpected EOF",
+		},
+		{
+			name:            "Validation error",
+			errorObj:        ErrValidation.Wrap(errors.New("Invalid state code.")),
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedCode:    "validation_failed",
+			expectedMessage: "Invalid state code.",
+		},
+		{
+			name:            "Wrapped not found error",
+			errorObj:        fmt.Errorf("Failed to get school object 1: %w", ErrNotFound.Wrap(errors.New("Record not found."))),
+			expectedStatus:  http.StatusNotFound,
+			expectedCode:    "not_found",
+			expectedMessage: "Failed to get school object 1: Record not found.",
+		},
+		{
+			name:            "Conflict error",
+			errorObj:        errTestConflict,
+			expectedStatus:  http.StatusConflict,
+			expectedCode:    "conflict",
+			expectedMessage: "Test Conflict",
+		},
+		{
+			name:            "Unclassified error",
+			errorObj:        errors.New("disk I/O error"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedCode:    "internal_error",
+			expectedMessage: "disk I/O error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			WriteApiErrorResponse(w, tc.errorObj)
+			assert.Equal(t, w.Code, tc.expectedStatus)
+
+			var actualResponse ResponseBody
+			err := json.Unmarshal(w.Body.Bytes(), &actualResponse)
+			assert.NilError(t, err, "Failed to unmarshal response body")
+
+			assert.Equal(t, *actualResponse.Error, tc.expectedMessage)
+			assert.Equal(t, *actualResponse.Code, tc.expectedCode)
+		})
+	}
+}
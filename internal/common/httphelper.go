@@ -2,13 +2,16 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
 type ResponseBody struct {
-	Message string  `json:"message"`
-	Data    any     `json:"data"`
-	Error   *string `json:"error,omitempty"`
+	Message string      `json:"message"`
+	Data    any         `json:"data"`
+	Error   *string     `json:"error,omitempty"`
+	Code    *string     `json:"code,omitempty"`
+	Errors  FieldErrors `json:"errors,omitempty"`
 }
 
 // WriteHttpResponse writes a JSON response with the given status code
@@ -39,14 +42,27 @@ func WriteCreatedResponse(w http.ResponseWriter, respBody ResponseBody) {
 // WriteErrorResponse writes an error response with the given status code
 func WriteErrorResponse(w http.ResponseWriter, err error, httpStatusCode int) {
 	errorMessage := err.Error()
+	errorCode := ErrorCode(err, httpStatusCode)
 	respBody := ResponseBody{
 		Message: "error",
 		Data:    nil,
 		Error:   &errorMessage,
+		Code:    &errorCode,
 	}
 	WriteHttpResponse(w, respBody, httpStatusCode)
 }
 
+// WriteApiErrorResponse writes an error response with the status of the
+// error's kind, or 500 Internal Server Error if it has none
+func WriteApiErrorResponse(w http.ResponseWriter, err error) {
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		WriteValidationErrorResponse(w, err)
+		return
+	}
+	WriteErrorResponse(w, err, ErrorStatus(err))
+}
+
 // WriteInternalErrorResponse writes a 500 Internal Server Error response
 func WriteInternalErrorResponse(w http.ResponseWriter, err error) {
 	WriteErrorResponse(w, err, http.StatusInternalServerError)
@@ -86,6 +86,7 @@ type WriteErrorTestCase struct {
 	writeFunc      func(w http.ResponseWriter, err error)
 	errorObj       error
 	expectedStatus int
+	expectedCode   string
 }
 
 func TestHttpHelper_WriteError(t *testing.T) {
@@ -95,42 +96,49 @@ func TestHttpHelper_WriteError(t *testing.T) {
 			writeFunc:      WriteInternalErrorResponse,
 			errorObj:       fmt.Errorf("Test Internal"),
 			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   "internal_error",
 		},
 		{
 			name:           "Test Not Found Error",
 			writeFunc:      WriteNotFoundResponse,
 			errorObj:       fmt.Errorf("Test Not Found"),
 			expectedStatus: http.StatusNotFound,
+			expectedCode:   "not_found",
 		},
 		{
 			name:           "Test Not Implemented Error",
 			writeFunc:      WriteNotImplementedResponse,
 			errorObj:       fmt.Errorf("Test Not Implemented"),
 			expectedStatus: http.StatusNotImplemented,
+			expectedCode:   "not_implemented",
 		},
 		{
 			name:           "Test Bad Request Error",
 			writeFunc:      WriteBadRequestResponse,
 			errorObj:       fmt.Errorf("Test Bad Request"),
 			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "bad_request",
 		},
 		{
 			name:           "Test Unauthorized Error",
 			writeFunc:      WriteUnauthorizedResponse,
 			errorObj:       fmt.Errorf("Test Unauthorized"),
 			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   "unauthorized",
 		},
 		{
 			name:           "Test Forbidden Error",
 			writeFunc:      WriteForbiddenResponse,
 			errorObj:       fmt.Errorf("Test Forbidden"),
 			expectedStatus: http.StatusForbidden,
+			expectedCode:   "forbidden",
 		},
 		{
 			name:           "Test Conflict Error",
 			writeFunc:      WriteConflictResponse,
 			errorObj:       fmt.Errorf("Test Conflict"),
 			expectedStatus: http.StatusConflict,
+			expectedCode:   "conflict",
 		},
 	}
 
@@ -157,6 +165,9 @@ func TestHttpHelper_WriteError(t *testing.T) {
 
 			// Test error field
 			assert.Equal(t, *actualResponse.Error, tc.errorObj.Error())
+
+			// Test code field
+			assert.Equal(t, *actualResponse.Code, tc.expectedCode)
 		})
 	}
 }
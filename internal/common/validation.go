@@ -0,0 +1,46 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FieldError is a single violation of a field of a model or request filter
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// FieldErrors is every violation found while validating one model or request
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// WriteValidationErrorResponse writes a 422 Unprocessable Entity response
+// listing every field error of err
+func WriteValidationErrorResponse(w http.ResponseWriter, err error) {
+	errorMessage := err.Error()
+	errorCode := ErrValidation.Code
+	respBody := ResponseBody{
+		Message: "error",
+		Data:    nil,
+		Error:   &errorMessage,
+		Code:    &errorCode,
+	}
+
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		respBody.Errors = fieldErrs
+	}
+
+	WriteHttpResponse(w, respBody, ErrValidation.Status)
+}
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type WriteValidationErrorTestCase struct {
+	name            string
+	errorObj        error
+	expectedMessage string
+	expectedErrors  FieldErrors
+}
+
+func TestValidation_WriteValidationError(t *testing.T) {
+	fieldErrs := FieldErrors{
+		{Field: "school_name", Code: "required", Message: "Value is required."},
+		{Field: "state_code", Code: "invalid_length", Message: "Value must be 2 characters long."},
+	}
+
+	testCases := []WriteValidationErrorTestCase{
+		{
+			name:            "Field errors",
+			errorObj:        ErrValidation.Wrap(fieldErrs),
+			expectedMessage: "school_name: Value is required.; state_code: Value must be 2 characters long.",
+			expectedErrors:  fieldErrs,
+		},
+		{
+			name:            "Wrapped field errors",
+			errorObj:        fmt.Errorf("Failed to create new school object: %w", ErrValidation.Wrap(fieldErrs)),
+			expectedMessage: "Failed to create new school object: school_name: Value is required.; state_code: Value must be 2 characters long.",
+			expectedErrors:  fieldErrs,
+		},
+		{
+			name:            "Validation error without fields",
+			errorObj:        ErrValidation.Wrap(errors.New("Invalid filter.")),
+			expectedMessage: "Invalid filter.",
+			expectedErrors:  nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			WriteApiErrorResponse(w, tc.errorObj)
+			assert.Equal(t, w.Code, http.StatusUnprocessableEntity)
+
+			var actualResponse ResponseBody
+			err := json.Unmarshal(w.Body.Bytes(), &actualResponse)
+			assert.NilError(t, err, "Failed to unmarshal response body")
+
+			assert.Equal(t, *actualResponse.Error, tc.expectedMessage)
+			assert.Equal(t, *actualResponse.Code, ErrValidation.Code)
+			assert.DeepEqual(t, actualResponse.Errors, tc.expectedErrors)
+		})
+	}
+}
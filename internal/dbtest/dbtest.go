@@ -0,0 +1,65 @@
+// Package dbtest creates SQLite databases with the schema of the migrations
+// for tests that run against a database
+package dbtest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// searchMigration creates the FTS5 school search index, which needs SQLite
+// built with the sqlite_fts5 tag
+const searchMigration = "000003_school_search.up.sql"
+
+// NewSession returns a session on a new database with every up migration
+// applied, closed when the test ends. Without FTS5 the school search
+// migration is left out, so schools are written without a search index.
+func NewSession(t testing.TB) *dbr.Session {
+	t.Helper()
+
+	conn, err := dbr.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"), nil)
+	if err != nil {
+		t.Fatalf("Failed to open test database: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	session := conn.NewSession(nil)
+
+	var fts5 bool
+	err = session.SelectBySql("SELECT sqlite_compileoption_used('ENABLE_FTS5')").LoadOne(&fts5)
+	if err != nil {
+		t.Fatalf("Failed to check for FTS5: %s", err)
+	}
+
+	migrations, err := filepath.Glob(filepath.Join(migrationsDir(), "*.up.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("Failed to find migrations in %s.", migrationsDir())
+	}
+	for _, migration := range migrations {
+		if !fts5 && filepath.Base(migration) == searchMigration {
+			t.Logf("Skipping %s, SQLite was built without FTS5.", searchMigration)
+			continue
+		}
+
+		script, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("Failed to read migration %s: %s", migration, err)
+		}
+		_, err = session.Exec(string(script))
+		if err != nil {
+			t.Fatalf("Failed to apply migration %s: %s", migration, err)
+		}
+	}
+
+	return session
+}
+
+// migrationsDir returns the migrations directory of the repository
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// CodeInvalidCursor is the field error code of a cursor that is malformed or
+// was issued for a different sort order
+const CodeInvalidCursor = "invalid_cursor"
+
+// cursor is the decoded form of a page cursor: the sort keys of the query
+// that issued it and the values of those keys on the row a page ends at
+type cursor struct {
+	Keys   []string `json:"k"`
+	Values []any    `json:"v"`
+}
+
+// signature identifies the sort keys, and their directions, a cursor is valid for
+func (s Sort) signature() []string {
+	keys := s.keys()
+	signature := make([]string, len(keys))
+	for i, key := range keys {
+		signature[i] = strings.ToLower(key.Field)
+		if key.Desc() {
+			signature[i] = "-" + signature[i]
+		}
+	}
+	return signature
+}
+
+// encodeCursor returns the opaque cursor pointing at row under the sort
+func encodeCursor(sort Sort, row reflect.Value) string {
+	keys := sort.keys()
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		values[i] = columnValue(row, key.Field)
+	}
+
+	data, _ := json.Marshal(cursor{Keys: sort.signature(), Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the sort key values of an opaque cursor, and false when
+// the cursor is malformed or was not issued for the sort
+func decodeCursor(sort Sort, token string) ([]any, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	c := cursor{}
+	err = decoder.Decode(&c)
+	if err != nil || !slices.Equal(c.Keys, sort.signature()) || len(c.Values) != len(c.Keys) {
+		return nil, false
+	}
+
+	for i, value := range c.Values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if integer, err := number.Int64(); err == nil {
+			c.Values[i] = integer
+		} else if float, err := number.Float64(); err == nil {
+			c.Values[i] = float
+		} else {
+			return nil, false
+		}
+	}
+	return c.Values, true
+}
+
+// columnValue returns the value of the field of a loaded model that maps to
+// column, matching field names the way dbr does when loading
+func columnValue(row reflect.Value, column string) any {
+	name := strings.ReplaceAll(column, "_", "")
+	field := reflect.Indirect(row).FieldByNameFunc(func(fieldName string) bool {
+		return strings.EqualFold(fieldName, name)
+	})
+	if !field.IsValid() {
+		return nil
+	}
+
+	if valuer, ok := field.Interface().(driver.Valuer); ok {
+		value, _ := valuer.Value()
+		return value
+	}
+	return field.Interface()
+}
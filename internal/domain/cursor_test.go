@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"gotest.tools/v3/assert"
+)
+
+type cursorRow struct {
+	Model
+	NTested       int
+	PctProficient float64
+}
+
+type DecodeCursorTestCase struct {
+	name           string
+	sort           Sort
+	token          string
+	expectedValues []any
+	expectedOk     bool
+}
+
+func TestCursor_Decode(t *testing.T) {
+	issuedFor := Sort{{Field: "pct_proficient", Dir: SortDesc}, {Field: "n_tested"}}
+	row := &cursorRow{Model: Model{Id: 7}, NTested: 40, PctProficient: 62.5}
+	token := encodeCursor(issuedFor, reflect.ValueOf(row))
+
+	testCases := []DecodeCursorTestCase{
+		{
+			name:           "Same sort",
+			sort:           issuedFor,
+			token:          token,
+			expectedValues: []any{62.5, int64(40), int64(7)},
+			expectedOk:     true,
+		},
+		{
+			name:  "Different direction",
+			sort:  Sort{{Field: "pct_proficient"}, {Field: "n_tested"}},
+			token: token,
+		},
+		{
+			name:  "Malformed",
+			sort:  issuedFor,
+			token: "not-a-cursor",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, ok := decodeCursor(tc.sort, tc.token)
+			assert.Equal(t, ok, tc.expectedOk)
+			assert.DeepEqual(t, values, tc.expectedValues)
+		})
+	}
+}
+
+type pageRow struct {
+	Id    int      `json:"id"`
+	Score *float64 `json:"score"`
+}
+
+type pageResponse struct {
+	ApiResponse
+	Data []*pageRow
+}
+
+// newPageDb returns a transaction on an in memory database with a table of
+// scores that tie and are NULL
+func newPageDb(t *testing.T) *dbr.Tx {
+	conn, err := dbr.Open("sqlite3", ":memory:", nil)
+	assert.NilError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	tx, err := conn.NewSession(nil).Begin()
+	assert.NilError(t, err)
+	t.Cleanup(tx.RollbackUnlessCommitted)
+
+	_, err = tx.Exec("CREATE TABLE page_item (id INTEGER PRIMARY KEY, score REAL, is_deleted BOOLEAN)")
+	assert.NilError(t, err)
+	_, err = tx.Exec("INSERT INTO page_item (id, score, is_deleted) VALUES (1, 90, FALSE), (2, NULL, FALSE), (3, 50, FALSE), (4, NULL, FALSE), (5, 90, FALSE), (6, 10, FALSE), (7, NULL, FALSE), (8, 50, FALSE)")
+	assert.NilError(t, err)
+
+	return tx
+}
+
+// queryPage returns the ids of a page of page_item rows and its cursors
+func queryPage(t *testing.T, tx *dbr.Tx, sort Sort, size int, cursors CursorSet) ([]int, CursorSet) {
+	table := Table{
+		Name:     "page_item",
+		Columns:  []string{"id", "score", "is_deleted"},
+		Sortable: []string{"id", "score"},
+	}
+	r := &Request{Sort: sort, PageSize: &size, Cursors: cursors}
+
+	resp, err := Query(
+		r,
+		tx,
+		table,
+		func() *pageResponse { return &pageResponse{} },
+		func(req *Request) *Request { return req },
+		func(resp *pageResponse) *ApiResponse { return &resp.ApiResponse },
+		func(resp *pageResponse) interface{} { return &resp.Data },
+		func() error {
+			v := &Validator{}
+			r.Validate(v, table)
+			return v.Err()
+		},
+		func(query *dbr.SelectStmt) *dbr.SelectStmt { return query },
+	)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.HasMore, resp.Cursors.Next != nil)
+
+	ids := make([]int, len(resp.Data))
+	for i, row := range resp.Data {
+		ids[i] = row.Id
+	}
+	return ids, resp.Cursors
+}
+
+type PagingTestCase struct {
+	name        string
+	sort        Sort
+	expectedIds []int
+}
+
+func TestCursor_Paging(t *testing.T) {
+	testCases := []PagingTestCase{
+		{
+			name:        "Ascending with NULLs first",
+			sort:        Sort{{Field: "score"}},
+			expectedIds: []int{2, 4, 7, 6, 3, 8, 1, 5},
+		},
+		{
+			name:        "Descending with NULLs last",
+			sort:        Sort{{Field: "score", Dir: SortDesc}},
+			expectedIds: []int{1, 5, 3, 8, 6, 2, 4, 7},
+		},
+		{
+			name:        "Ties broken by descending id",
+			sort:        Sort{{Field: "score"}, {Field: "id", Dir: SortDesc}},
+			expectedIds: []int{7, 4, 2, 6, 8, 3, 5, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, size := range []int{1, 2, 3} {
+			t.Run(fmt.Sprintf("%s, page size %d", tc.name, size), func(t *testing.T) {
+				tx := newPageDb(t)
+
+				// Walk forward through every page
+				var ids []int
+				var pages []CursorSet
+				cursors := CursorSet{}
+				for {
+					page, pageCursors := queryPage(t, tx, tc.sort, size, cursors)
+					ids = append(ids, page...)
+					pages = append(pages, pageCursors)
+					if pageCursors.Next == nil {
+						break
+					}
+					cursors = CursorSet{Next: pageCursors.Next}
+				}
+				assert.DeepEqual(t, ids, tc.expectedIds)
+
+				// Walk back from the last page to the first
+				var backIds []int
+				cursors = CursorSet{Prev: pages[len(pages)-1].Prev}
+				for cursors.Prev != nil {
+					page, pageCursors := queryPage(t, tx, tc.sort, size, cursors)
+					backIds = append(slices.Clone(page), backIds...)
+					cursors = CursorSet{Prev: pageCursors.Prev}
+				}
+				lastPage := (len(tc.expectedIds) - 1) / size * size
+				assert.DeepEqual(t, backIds, tc.expectedIds[:lastPage])
+			})
+		}
+	}
+}
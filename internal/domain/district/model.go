@@ -0,0 +1,260 @@
+package district
+
+import (
+	"academic-api/internal/domain"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type District struct {
+	domain.Model
+	DistrictName string `json:"district_name"`
+	StateCode    string `json:"state_code"`
+	// Local education agency id assigned by the state or NCES
+	LeaId *string `json:"lea_id"`
+	// School and proficiency totals, embedded by queries with include=rollup
+	Rollup *Rollup `json:"rollup,omitempty" db:"-"`
+}
+
+func NewDistrict(name string, state string, leaId *string) *District {
+	return &District{
+		DistrictName: name,
+		StateCode:    state,
+		LeaId:        leaId,
+	}
+}
+
+func (d *District) ValidateCreate() error {
+	v := &domain.Validator{}
+	d.validateFields(v)
+	return v.Err()
+}
+
+func (d *District) ValidateUpdate() error {
+	if d.IsDeleted.Bool {
+		return domain.ErrDeleted
+	}
+
+	v := &domain.Validator{}
+	v.Min("id", d.Id, 1)
+	d.validateFields(v)
+	return v.Err()
+}
+
+func (d *District) validateFields(v *domain.Validator) {
+	v.Required("district_name", d.DistrictName)
+	v.Length("state_code", d.StateCode, 2)
+	if d.LeaId != nil {
+		v.Required("lea_id", *d.LeaId)
+	}
+}
+
+func (d *District) Create(db *dbr.Tx) error {
+	err := d.ValidateCreate()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to validate district content for create.")
+		return domain.ValidationError(err)
+	}
+
+	// Set timestamps
+	now := time.Now()
+	d.CreatedAt = domain.NullTime{
+		NullTime: sql.NullTime{Time: now, Valid: true},
+	}
+	d.UpdatedAt = domain.NullTime{
+		NullTime: sql.NullTime{Time: now, Valid: true},
+	}
+	d.IsDeleted = domain.NullBool{
+		NullBool: sql.NullBool{Bool: false, Valid: true},
+	}
+
+	err = db.InsertInto("district").
+		Columns("district_name", "state_code", "lea_id", "created_at", "updated_at", "is_deleted").
+		Record(d).
+		Returning("id", "created_at", "updated_at").
+		Load(d)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert district to database.")
+		return domain.DbError(err)
+	}
+
+	return nil
+}
+
+// checkSchools checks that the schools of the district are in its state, so
+// the state of a district with schools cannot be changed
+func (d *District) checkSchools(db *dbr.Tx) error {
+	var count int
+	err := db.Select("COUNT(*)").
+		From("school").
+		Where("district_id = ?", d.Id).
+		Where("state_code <> ?", d.StateCode).
+		LoadOne(&count)
+	if err != nil {
+		return err
+	}
+
+	v := &domain.Validator{}
+	v.Check(count == 0, "state_code", domain.CodeInvalidChoice, "Value must match the state of the schools in the district.")
+	return v.Err()
+}
+
+// Update saves the district and renames it on the schools it contains
+func (d *District) Update(db *dbr.Tx) error {
+	err := d.ValidateUpdate()
+	if err == nil {
+		err = d.checkSchools(db)
+	}
+	if err != nil {
+		return domain.ValidationError(err)
+	}
+
+	err = db.Update("district").
+		Set("district_name", d.DistrictName).
+		Set("state_code", d.StateCode).
+		Set("lea_id", d.LeaId).
+		Set("updated_at", time.Now()).
+		Where("id = ?", d.Id).
+		Returning("updated_at").
+		Load(d)
+	if err != nil {
+		return domain.DbError(err)
+	}
+
+	_, err = db.Update("school").
+		Set("district_name", d.DistrictName).
+		Where("district_id = ?", d.Id).
+		Where("district_name <> ?", d.DistrictName).
+		Exec()
+	return err
+}
+
+func (d *District) Delete(db *dbr.Tx) error {
+	if d.IsDeleted.Bool {
+		return domain.ErrDeleted
+	}
+
+	err := db.Update("district").
+		Set("is_deleted", true).
+		Set("deleted_at", time.Now()).
+		Where("id = ?", d.Id).
+		Returning("is_deleted", "deleted_at").
+		Load(d)
+
+	return err
+}
+
+func (d *District) Restore(db *dbr.Tx) error {
+	if !d.IsDeleted.Bool {
+		return domain.ErrNotDeleted
+	}
+
+	err := db.Update("district").
+		Set("is_deleted", false).
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Where("id = ?", d.Id).
+		Returning("is_deleted", "deleted_at", "updated_at").
+		Load(d)
+
+	return err
+}
+
+// Load returns the district with the given id, including soft deleted districts
+func Load(db dbr.SessionRunner, id int) (*District, error) {
+	d := &District{}
+	err := db.Select("*").
+		From("district").
+		Where("id = ?", id).
+		LoadOne(d)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// LoadByName returns the district of the state with the given name, ignoring
+// case, including soft deleted districts
+func LoadByName(db dbr.SessionRunner, state string, name string) (*District, error) {
+	d := &District{}
+	err := db.Select("*").
+		From("district").
+		Where("state_code = ?", state).
+		Where("district_name = ? COLLATE NOCASE", strings.TrimSpace(name)).
+		LoadOne(d)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// LoadMany returns the districts with the given ids keyed by id, including
+// soft deleted districts
+func LoadMany(db dbr.SessionRunner, ids []int) (map[int]*District, error) {
+	districts := map[int]*District{}
+	if len(ids) == 0 {
+		return districts, nil
+	}
+
+	var rows []*District
+	_, err := db.Select("*").
+		From("district").
+		Where(dbr.Eq("id", ids)).
+		Load(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range rows {
+		districts[d.Id] = d
+	}
+	return districts, nil
+}
+
+// DeletedBefore returns the ids of districts soft deleted before the given time
+func DeletedBefore(db dbr.SessionRunner, before time.Time) ([]int, error) {
+	var ids []int
+	_, err := db.Select("id").
+		From("district").
+		Where("is_deleted = ?", true).
+		Where("deleted_at < ?", before).
+		Load(&ids)
+	return ids, err
+}
+
+// Purge permanently deletes the districts with the given ids. Their schools
+// are kept and no longer belong to a district.
+func Purge(db *dbr.Tx, ids []int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	_, err := db.Update("school").
+		Set("district_id", nil).
+		Where("district_id IN ?", ids).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := db.DeleteFrom("district").
+		Where("id IN ?", ids).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
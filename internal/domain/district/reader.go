@@ -0,0 +1,147 @@
+package district
+
+import (
+	"academic-api/internal/domain"
+	"net/url"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// Table is the district table and the columns its queries may select, sort
+// and facet by
+var Table = domain.Table{
+	Name:      "district",
+	Columns:   []string{"id", "district_name", "state_code", "lea_id", "is_deleted", "created_at", "updated_at", "deleted_at"},
+	Sortable:  []string{"id", "district_name", "state_code"},
+	Facetable: []string{"state_code"},
+	// Totals of the schools and reports of each district, see Rollup
+	Includable: []string{"rollup"},
+}
+
+type DistrictRequest struct {
+	domain.Request
+	StateCode    *domain.Filter[string] `json:"state_code"`
+	DistrictName *domain.Filter[string] `json:"district_name"`
+	LeaId        *domain.Filter[string] `json:"lea_id"`
+	// Academic year the rollup reports are limited to, all years if nil
+	RollupYear *int `json:"rollup_year"`
+}
+
+type DistrictResponse struct {
+	domain.ApiResponse
+	Data []*District
+}
+
+func (r DistrictResponse) MarshalJSON() ([]byte, error) {
+	type response DistrictResponse
+	return domain.SparseJSON(response(r), &r.ApiResponse)
+}
+
+func (r *DistrictRequest) ParseQuery(values url.Values) error {
+	err := r.Request.ParseQuery(values)
+	if err != nil {
+		return err
+	}
+
+	r.StateCode, err = domain.QueryStringFilter(values, "state_code")
+	if err != nil {
+		return err
+	}
+
+	r.DistrictName, err = domain.QueryStringFilter(values, "district_name")
+	if err != nil {
+		return err
+	}
+
+	r.LeaId, err = domain.QueryStringFilter(values, "lea_id")
+	if err != nil {
+		return err
+	}
+
+	r.RollupYear, err = domain.QueryInt(values, "rollup_year")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *DistrictRequest) ValidateFilter() error {
+	v := &domain.Validator{}
+	r.Request.Validate(v, Table)
+
+	r.StateCode.Validate(v, "state_code")
+	r.DistrictName.Validate(v, "district_name")
+	r.LeaId.Validate(v, "lea_id")
+
+	for _, stateCode := range r.StateCode.Values() {
+		v.Length("state_code", stateCode, 2)
+	}
+	if r.RollupYear != nil {
+		v.Check(r.Includes("rollup"), "rollup_year", domain.CodeInvalidChoice, "Value requires include=rollup.")
+		v.Min("rollup_year", *r.RollupYear, 1)
+	}
+
+	return v.Err()
+}
+
+func (r *DistrictRequest) ApplyFilters(query *dbr.SelectStmt) *dbr.SelectStmt {
+	if r.Id != nil {
+		query = query.Where("id = ?", *r.Id)
+	}
+
+	query = r.StateCode.Apply(query, "state_code")
+	query = r.DistrictName.Apply(query, "district_name")
+	query = r.LeaId.Apply(query, "lea_id")
+
+	return query
+}
+
+func (r *DistrictRequest) ApplyCursors(query *dbr.SelectStmt) *dbr.SelectStmt {
+	return domain.ApplyCursors(&r.Request, Table, query)
+}
+
+func (r *DistrictRequest) Query(db *dbr.Tx) (*DistrictResponse, error) {
+	resp, err := domain.Query(
+		r,
+		db,
+		Table,
+		func() *DistrictResponse { return &DistrictResponse{} },
+		func(req *DistrictRequest) *domain.Request { return &req.Request },
+		func(resp *DistrictResponse) *domain.ApiResponse { return &resp.ApiResponse },
+		func(resp *DistrictResponse) interface{} { return &resp.Data },
+		r.ValidateFilter,
+		r.ApplyFilters,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Includes("rollup") {
+		err = includeRollups(db, resp.Data, r.RollupYear)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// includeRollups embeds the rollup of each district, loading every rollup of
+// the page in one query
+func includeRollups(db *dbr.Tx, districts []*District, academicYear *int) error {
+	ids := make([]int, 0, len(districts))
+	for _, d := range districts {
+		ids = append(ids, d.Id)
+	}
+
+	rollups, err := LoadRollups(db, ids, academicYear)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range districts {
+		d.Rollup = rollups[d.Id]
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package district
+
+import (
+	"github.com/gocraft/dbr/v2"
+)
+
+// Rollup aggregates the live schools of a district and their live reports
+type Rollup struct {
+	SchoolCount int `json:"school_count"`
+	ReportCount int `json:"report_count"`
+	// Students tested and proficient in the all students, grades 3-8 reports.
+	// Narrower reports are left out as they count the same students again.
+	NTested     int `json:"n_tested"`
+	NProficient int `json:"n_proficient"`
+	// Nil when no student was tested
+	PctProficient *float64 `json:"pct_proficient"`
+}
+
+// rollupRow is a rollup along with the district it belongs to
+type rollupRow struct {
+	DistrictId int
+	Rollup
+}
+
+// LoadRollups returns the rollups of the districts with the given ids keyed
+// by id. Reports are limited to academicYear unless it is nil. Districts
+// without live schools get an empty rollup.
+func LoadRollups(db dbr.SessionRunner, ids []int, academicYear *int) (map[int]*Rollup, error) {
+	rollups := map[int]*Rollup{}
+	if len(ids) == 0 {
+		return rollups, nil
+	}
+
+	reportJoin := "school_report.school_id = school.id AND COALESCE(school_report.is_deleted, FALSE) = FALSE"
+	joinArgs := []any{}
+	if academicYear != nil {
+		reportJoin += " AND school_report.academic_year = ?"
+		joinArgs = append(joinArgs, *academicYear)
+	}
+
+	var rows []*rollupRow
+	_, err := db.Select(
+		"school.district_id AS district_id",
+		"COUNT(DISTINCT school.id) AS school_count",
+		"COUNT(school_report.id) AS report_count",
+		"COALESCE(SUM(school_report.n_tested) FILTER (WHERE school_report.grade_level = '3-8' AND school_report.demographic_group = 'all'), 0) AS n_tested",
+		"COALESCE(SUM(school_report.n_proficient) FILTER (WHERE school_report.grade_level = '3-8' AND school_report.demographic_group = 'all'), 0) AS n_proficient",
+	).
+		From("school").
+		LeftJoin("school_report", dbr.Expr(reportJoin, joinArgs...)).
+		Where(dbr.Eq("school.district_id", ids)).
+		Where("COALESCE(school.is_deleted, FALSE) = FALSE").
+		GroupBy("school.district_id").
+		Load(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		rollups[id] = &Rollup{}
+	}
+	for _, row := range rows {
+		if row.NTested > 0 {
+			pct := float64(row.NProficient) / float64(row.NTested) * 100
+			row.PctProficient = &pct
+		}
+		rollups[row.DistrictId] = &row.Rollup
+	}
+	return rollups, nil
+}
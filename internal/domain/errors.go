@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"academic-api/internal/common"
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrNotFound is returned when no record matches the requested id
+var ErrNotFound = common.ErrNotFound.Wrap(errors.New("Record not found."))
+
+// ErrDeleted is returned when modifying a soft deleted record
+var ErrDeleted = common.ErrConflict.Wrap(errors.New("Record is deleted."))
+
+// ErrNotDeleted is returned when restoring a record that is not soft deleted
+var ErrNotDeleted = common.ErrConflict.Wrap(errors.New("Record is not deleted."))
+
+// DbError classifies SQLite constraint violations. Duplicate keys and missing
+// references are conflicts, CHECK and NOT NULL violations are invalid values.
+// Other errors are returned unchanged.
+func DbError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey, sqlite3.ErrConstraintForeignKey:
+		return common.ErrConflict.Wrap(err)
+	case sqlite3.ErrConstraintCheck, sqlite3.ErrConstraintNotNull:
+		return common.ErrValidation.Wrap(err)
+	default:
+		return err
+	}
+}
+
+// ValidationError classifies err as an invalid value unless it already has a kind
+func ValidationError(err error) error {
+	var apiErr *common.ApiError
+	if err == nil || errors.As(err, &apiErr) {
+		return err
+	}
+	return common.ErrValidation.Wrap(err)
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Require selects columns the response is built from even when the requested
+// fields leave them out, such as the foreign key of an included relation
+func (r *Request) Require(columns ...string) {
+	r.required = append(r.required, columns...)
+}
+
+// Compute adds a column computed by a SQL expression, which the request then
+// selects and may sort by. The expression is inserted into queries as is, so
+// any user input in it must already be escaped.
+func (r *Request) Compute(name string, expr string) {
+	if r.computed == nil {
+		r.computed = map[string]string{}
+	}
+	r.computed[name] = expr
+}
+
+// expr returns the SQL expression of a column
+func (r *Request) expr(column string) string {
+	if expr, ok := r.computed[column]; ok {
+		return "(" + expr + ")"
+	}
+	return column
+}
+
+// Includes reports whether the request asks to embed the relation
+func (r *Request) Includes(relation string) bool {
+	return slices.Contains(r.Include, relation)
+}
+
+// columns returns the columns to select: every column unless fields are
+// requested, in which case the fields along with the id, the sort keys and
+// required columns. Computed columns are always selected.
+func (r *Request) columns() []any {
+	names := []string{"*"}
+	if len(r.Fields) > 0 {
+		names = slices.Clone(r.Fields)
+		for _, key := range r.Sort.keys() {
+			names = append(names, key.Field)
+		}
+		names = append(names, r.required...)
+	}
+	names = append(names, slices.Sorted(maps.Keys(r.computed))...)
+
+	selected := []any{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if _, ok := r.computed[name]; ok {
+			selected = append(selected, r.expr(name)+" AS "+name)
+		} else {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// outputFields returns the JSON fields kept in each returned row, or nil to
+// keep them all
+func (r *Request) outputFields() []string {
+	if len(r.Fields) == 0 {
+		return nil
+	}
+	return slices.Concat([]string{"id"}, r.Fields, r.Include, slices.Sorted(maps.Keys(r.computed)))
+}
+
+// SparseJSON marshals a query response whose rows are in its Data field,
+// keeping only the fields requested of each row
+func SparseJSON(resp any, apiResp *ApiResponse) ([]byte, error) {
+	data, err := json.Marshal(resp)
+	if err != nil || apiResp.fields == nil {
+		return data, err
+	}
+
+	body := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []map[string]json.RawMessage{}
+	err = json.Unmarshal(body["Data"], &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		maps.DeleteFunc(row, func(field string, _ json.RawMessage) bool {
+			return !slices.Contains(apiResp.fields, field)
+		})
+	}
+
+	body["Data"], err = json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(body)
+}
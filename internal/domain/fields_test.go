@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type sparseRow struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+}
+
+type sparseResponse struct {
+	ApiResponse
+	Data []*sparseRow
+}
+
+type SparseJSONTestCase struct {
+	name         string
+	fields       []string
+	expectedBody string
+}
+
+func TestFields_SparseJSON(t *testing.T) {
+	testCases := []SparseJSONTestCase{
+		{
+			name:         "Every field",
+			expectedBody: `{"cursors":{"prev":null,"next":null},"page_size":null,"has_more":false,"Data":[{"id":1,"name":"Alpha","subject":"math"}]}`,
+		},
+		{
+			name:         "Requested fields",
+			fields:       []string{"id", "subject"},
+			expectedBody: `{"Data":[{"id":1,"subject":"math"}],"cursors":{"prev":null,"next":null},"has_more":false,"page_size":null}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := sparseResponse{Data: []*sparseRow{{Id: 1, Name: "Alpha", Subject: "math"}}}
+			resp.fields = tc.fields
+
+			body, err := SparseJSON(resp, &resp.ApiResponse)
+			assert.NilError(t, err)
+			assert.Equal(t, string(body), tc.expectedBody)
+		})
+	}
+}
+
+type NormalizeTestCase struct {
+	name             string
+	request          Request
+	expectedFields   []string
+	expectedIncludes bool
+	expectedFacets   []string
+	expectedSort     Sort
+}
+
+func TestFields_Normalize(t *testing.T) {
+	table := Table{
+		Columns:    []string{"id", "name", "subject"},
+		Sortable:   []string{"id", "name"},
+		Facetable:  []string{"subject"},
+		Includable: []string{"school"},
+	}
+
+	testCases := []NormalizeTestCase{
+		{
+			name:             "Lowercase",
+			request:          Request{Fields: []string{"name"}, Include: []string{"school"}, Facets: []string{"subject"}, Sort: Sort{{Field: "name", Dir: SortDesc}}},
+			expectedFields:   []string{"id", "name", "school"},
+			expectedIncludes: true,
+			expectedFacets:   []string{"subject"},
+			expectedSort:     Sort{{Field: "name", Dir: SortDesc}},
+		},
+		{
+			name:             "Mixed case",
+			request:          Request{Fields: []string{" Name"}, Include: []string{"SCHOOL"}, Facets: []string{"Subject "}, Sort: Sort{{Field: "NAME", Dir: "DESC"}}},
+			expectedFields:   []string{"id", "name", "school"},
+			expectedIncludes: true,
+			expectedFacets:   []string{"subject"},
+			expectedSort:     Sort{{Field: "name", Dir: SortDesc}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &Validator{}
+			tc.request.Validate(v, table)
+			assert.NilError(t, v.Err())
+
+			assert.DeepEqual(t, tc.request.outputFields(), tc.expectedFields)
+			assert.Equal(t, tc.request.Includes("school"), tc.expectedIncludes)
+			assert.DeepEqual(t, tc.request.Facets, tc.expectedFacets)
+			assert.DeepEqual(t, tc.request.Sort, tc.expectedSort)
+		})
+	}
+}
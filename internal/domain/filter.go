@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"academic-api/internal/common"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// Filter operators
+const (
+	OpEq      = "eq"
+	OpIn      = "in"
+	OpGte     = "gte"
+	OpLte     = "lte"
+	OpFrom    = "from"
+	OpTo      = "to"
+	OpBetween = "between"
+	OpLike    = "like"
+)
+
+// Filter matches a column against one or more operators, which must all hold.
+// In JSON a filter is a scalar (eq), an array (in) or an object keyed by
+// operator; from and to are aliases of gte and lte. Like takes a SQL LIKE
+// pattern and is only accepted on text columns.
+type Filter[T cmp.Ordered] struct {
+	Eq      *T      `json:"eq,omitempty"`
+	In      []T     `json:"in,omitempty"`
+	Gte     *T      `json:"gte,omitempty"`
+	Lte     *T      `json:"lte,omitempty"`
+	Between []T     `json:"between,omitempty"`
+	Like    *string `json:"like,omitempty"`
+}
+
+// filterOps is the JSON object form of a Filter
+type filterOps[T cmp.Ordered] struct {
+	Eq      *T      `json:"eq"`
+	In      []T     `json:"in"`
+	Gte     *T      `json:"gte"`
+	Lte     *T      `json:"lte"`
+	From    *T      `json:"from"`
+	To      *T      `json:"to"`
+	Between []T     `json:"between"`
+	Like    *string `json:"like"`
+}
+
+// Eq returns a filter matching value exactly
+func Eq[T cmp.Ordered](value T) *Filter[T] {
+	return &Filter[T]{Eq: &value}
+}
+
+func (f *Filter[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	switch data[0] {
+	case '[':
+		f.In = []T{}
+		return json.Unmarshal(data, &f.In)
+	case '{':
+		ops := filterOps[T]{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&ops)
+		if err != nil {
+			return err
+		}
+
+		f.Eq = ops.Eq
+		f.In = ops.In
+		f.Gte = cmp.Or(ops.Gte, ops.From)
+		f.Lte = cmp.Or(ops.Lte, ops.To)
+		f.Between = ops.Between
+		f.Like = ops.Like
+		return nil
+	default:
+		return json.Unmarshal(data, &f.Eq)
+	}
+}
+
+// Values returns every operand of the filter except the like pattern
+func (f *Filter[T]) Values() []T {
+	if f == nil {
+		return nil
+	}
+
+	var values []T
+	for _, value := range []*T{f.Eq, f.Gte, f.Lte} {
+		if value != nil {
+			values = append(values, *value)
+		}
+	}
+	values = append(values, f.In...)
+	values = append(values, f.Between...)
+	return values
+}
+
+// Map replaces every operand of the filter except the like pattern by fn of it
+func (f *Filter[T]) Map(fn func(T) T) {
+	if f == nil {
+		return
+	}
+
+	for _, value := range []*T{f.Eq, f.Gte, f.Lte} {
+		if value != nil {
+			*value = fn(*value)
+		}
+	}
+	for i := range f.In {
+		f.In[i] = fn(f.In[i])
+	}
+	for i := range f.Between {
+		f.Between[i] = fn(f.Between[i])
+	}
+}
+
+// Validate checks that the operators of the filter on field are well formed
+func (f *Filter[T]) Validate(v *Validator, field string) {
+	if f == nil {
+		return
+	}
+
+	if f.In != nil && len(f.In) == 0 {
+		v.Add(field, CodeInvalidOperator, "Operator in requires at least one value.")
+	}
+
+	if f.Between != nil {
+		if len(f.Between) != 2 {
+			v.Add(field, CodeInvalidOperator, "Operator between requires exactly two values.")
+		} else if f.Between[0] > f.Between[1] {
+			v.Add(field, CodeOutOfRange, "Lower bound of between exceeds its upper bound.")
+		}
+	}
+
+	if f.Gte != nil && f.Lte != nil && *f.Gte > *f.Lte {
+		v.Add(field, CodeOutOfRange, "Lower bound exceeds upper bound.")
+	}
+
+	if _, text := any(*new(T)).(string); f.Like != nil && !text {
+		v.Add(field, CodeInvalidOperator, "Operator like is only supported on text fields.")
+	}
+}
+
+// Apply adds the conditions of the filter on column to query
+func (f *Filter[T]) Apply(query *dbr.SelectStmt, column string) *dbr.SelectStmt {
+	if f == nil {
+		return query
+	}
+
+	if f.Eq != nil {
+		query = query.Where(dbr.Eq(column, *f.Eq))
+	}
+
+	if len(f.In) > 0 {
+		query = query.Where(dbr.Eq(column, f.In))
+	}
+
+	if f.Gte != nil {
+		query = query.Where(dbr.Gte(column, *f.Gte))
+	}
+
+	if f.Lte != nil {
+		query = query.Where(dbr.Lte(column, *f.Lte))
+	}
+
+	if len(f.Between) == 2 {
+		query = query.Where(dbr.And(dbr.Gte(column, f.Between[0]), dbr.Lte(column, f.Between[1])))
+	}
+
+	if f.Like != nil {
+		query = query.Where(dbr.Like(column, *f.Like))
+	}
+
+	return query
+}
+
+// QueryFilter reads the filter on key from URL query parameters. The plain key
+// matches exactly and key[op] applies any other operator, with in and between
+// taking comma separated values. It returns nil when no parameter is present.
+func QueryFilter[T cmp.Ordered](values url.Values, key string, parse func(string) (T, error)) (*Filter[T], error) {
+	var f *Filter[T]
+	parseOne := func(param string, raw string) (*T, error) {
+		value, err := parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, common.ErrBadRequest.Wrap(fmt.Errorf("Invalid value for %s: %s", param, raw))
+		}
+		return &value, nil
+	}
+	parseList := func(param string, raw string) ([]T, error) {
+		list := []T{}
+		for _, item := range strings.Split(raw, ",") {
+			value, err := parseOne(param, item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, *value)
+		}
+		return list, nil
+	}
+
+	for _, op := range []string{"", OpEq, OpIn, OpGte, OpFrom, OpLte, OpTo, OpBetween, OpLike} {
+		param := key
+		if op != "" {
+			param = fmt.Sprintf("%s[%s]", key, op)
+		}
+		if !values.Has(param) {
+			continue
+		}
+		raw := values.Get(param)
+		if f == nil {
+			f = &Filter[T]{}
+		}
+
+		var err error
+		switch op {
+		case "", OpEq:
+			f.Eq, err = parseOne(param, raw)
+		case OpIn:
+			f.In, err = parseList(param, raw)
+		case OpGte, OpFrom:
+			f.Gte, err = parseOne(param, raw)
+		case OpLte, OpTo:
+			f.Lte, err = parseOne(param, raw)
+		case OpBetween:
+			f.Between, err = parseList(param, raw)
+		case OpLike:
+			f.Like = &raw
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// QueryIntFilter reads an integer filter from URL query parameters
+func QueryIntFilter(values url.Values, key string) (*Filter[int], error) {
+	return QueryFilter(values, key, strconv.Atoi)
+}
+
+// QueryFloatFilter reads a decimal filter from URL query parameters
+func QueryFloatFilter(values url.Values, key string) (*Filter[float64], error) {
+	return QueryFilter(values, key, func(raw string) (float64, error) {
+		return strconv.ParseFloat(raw, 64)
+	})
+}
+
+// QueryStringFilter reads a text filter from URL query parameters
+func QueryStringFilter(values url.Values, key string) (*Filter[string], error) {
+	return QueryFilter(values, key, func(raw string) (string, error) {
+		return raw, nil
+	})
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type FilterUnmarshalTestCase struct {
+	name             string
+	body             string
+	expectedResult   *Filter[int]
+	expectedErrorMsg string
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestFilter_UnmarshalJSON(t *testing.T) {
+	testCases := []FilterUnmarshalTestCase{
+		{
+			name:           "Scalar",
+			body:           `2024`,
+			expectedResult: &Filter[int]{Eq: ptr(2024)},
+		},
+		{
+			name:           "Array",
+			body:           `[1, 2, 3]`,
+			expectedResult: &Filter[int]{In: []int{1, 2, 3}},
+		},
+		{
+			name:           "Operators",
+			body:           `{"gte": 2019, "lte": 2023, "between": [2020, 2022]}`,
+			expectedResult: &Filter[int]{Gte: ptr(2019), Lte: ptr(2023), Between: []int{2020, 2022}},
+		},
+		{
+			name:           "From and to aliases",
+			body:           `{"from": 2019, "to": 2023}`,
+			expectedResult: &Filter[int]{Gte: ptr(2019), Lte: ptr(2023)},
+		},
+		{
+			name:             "Unknown operator",
+			body:             `{"gt": 2019}`,
+			expectedErrorMsg: `json: unknown field "gt"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &Filter[int]{}
+			err := json.Unmarshal([]byte(tc.body), f)
+			if tc.expectedErrorMsg != "" {
+				assert.Error(t, err, tc.expectedErrorMsg)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, f, tc.expectedResult)
+		})
+	}
+}
+
+type QueryFilterTestCase struct {
+	name             string
+	query            string
+	expectedResult   *Filter[int]
+	expectedErrorMsg string
+}
+
+func TestFilter_QueryIntFilter(t *testing.T) {
+	testCases := []QueryFilterTestCase{
+		{
+			name:           "Absent",
+			query:          "other=1",
+			expectedResult: nil,
+		},
+		{
+			name:           "Plain key",
+			query:          "academic_year=2024",
+			expectedResult: &Filter[int]{Eq: ptr(2024)},
+		},
+		{
+			name:           "In list",
+			query:          "academic_year[in]=2019,2021",
+			expectedResult: &Filter[int]{In: []int{2019, 2021}},
+		},
+		{
+			name:           "From and to",
+			query:          "academic_year[from]=2019&academic_year[to]=2023",
+			expectedResult: &Filter[int]{Gte: ptr(2019), Lte: ptr(2023)},
+		},
+		{
+			name:             "Invalid value",
+			query:            "academic_year[between]=2019,soon",
+			expectedErrorMsg: "Invalid value for academic_year[between]: soon",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query)
+			assert.NilError(t, err)
+
+			f, err := QueryIntFilter(values, "academic_year")
+			if tc.expectedErrorMsg != "" {
+				assert.Error(t, err, tc.expectedErrorMsg)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, f, tc.expectedResult)
+		})
+	}
+}
+
+type FilterValidateTestCase struct {
+	name          string
+	filter        *Filter[int]
+	expectedCodes []string
+}
+
+func TestFilter_Validate(t *testing.T) {
+	testCases := []FilterValidateTestCase{
+		{
+			name:   "Valid range",
+			filter: &Filter[int]{Gte: ptr(2019), Lte: ptr(2023)},
+		},
+		{
+			name:          "Empty in list",
+			filter:        &Filter[int]{In: []int{}},
+			expectedCodes: []string{CodeInvalidOperator},
+		},
+		{
+			name:          "Reversed bounds",
+			filter:        &Filter[int]{Gte: ptr(2023), Between: []int{2023, 2019}, Lte: ptr(2019)},
+			expectedCodes: []string{CodeOutOfRange, CodeOutOfRange},
+		},
+		{
+			name:          "Like on integers",
+			filter:        &Filter[int]{Like: ptr("20%")},
+			expectedCodes: []string{CodeInvalidOperator},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &Validator{}
+			tc.filter.Validate(v, "academic_year")
+
+			var codes []string
+			for _, fieldErr := range v.errs {
+				codes = append(codes, fieldErr.Code)
+			}
+			assert.DeepEqual(t, codes, tc.expectedCodes)
+		})
+	}
+}
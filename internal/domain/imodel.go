@@ -19,6 +19,9 @@ type IModel interface {
 
 	// Soft deletes records in the database
 	Delete(db *dbr.Tx) error
+
+	// Restores a soft deleted record in the database
+	Restore(db *dbr.Tx) error
 }
 
 type Model struct {
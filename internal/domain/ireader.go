@@ -1,66 +1,243 @@
 package domain
 
 import (
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
 	"github.com/gocraft/dbr/v2"
 )
 
+// CursorSet holds the opaque cursors of the pages before and after a page
 type CursorSet struct {
-	Prev *int `json:"prev"`
-	Next *int `json:"next"`
+	Prev *string `json:"prev"`
+	Next *string `json:"next"`
 }
 
 type ApiResponse struct {
 	Cursors  CursorSet `json:"cursors"`
 	PageSize *int      `json:"page_size"`
+	// More rows follow this page and can be fetched with the next cursor
+	HasMore bool `json:"has_more"`
+	// Rows matching the filters across all pages, when include_total is set
+	TotalCount *int `json:"total_count,omitempty"`
+	// Row counts per value of each requested facet column
+	Facets map[string][]FacetCount `json:"facets,omitempty"`
+
+	// Fields kept in each row by SparseJSON, nil to keep them all
+	fields []string
+}
+
+// FacetCount is the number of matching rows with a value in a facet column
+type FacetCount struct {
+	Value any `json:"value"`
+	Count int `json:"count"`
 }
 
 type IRequest interface {
+	ParseQuery(values url.Values) error
 	ValidateFilter() error
 	ApplyFilters(query *dbr.SelectStmt) *dbr.SelectStmt
-	ApplyCursors(query *dbr.SelectStmt, response *ApiResponse)
+	ApplyCursors(query *dbr.SelectStmt) *dbr.SelectStmt
 	Query(db *dbr.Tx) (*ApiResponse, error)
 }
 
+// Table describes the table an entity is queried from, the columns a request
+// may select, sort by and ask facet counts of, and the relations it may embed
+type Table struct {
+	Name       string
+	Columns    []string
+	Sortable   []string
+	Facetable  []string
+	Includable []string
+}
+
 type Request struct {
 	Cursors  CursorSet `json:"cursors"`
 	PageSize *int      `json:"page_size"`
 	Id       *int      `json:"id"`
+	// Columns to order results by, see Table.Sortable
+	Sort Sort `json:"sort"`
+	// Include soft deleted records alongside live ones (admin only)
+	IncludeDeleted bool `json:"include_deleted"`
+	// Return soft deleted records only (admin only)
+	OnlyDeleted bool `json:"only_deleted"`
+	// Count the rows matching the filters across all pages
+	IncludeTotal bool `json:"include_total"`
+	// Columns to count matching rows per value of, see Table.Facetable
+	Facets []string `json:"facets"`
+	// Columns to return, see Table.Columns. Every column is returned if empty.
+	Fields []string `json:"fields"`
+	// Related records to embed in each row, see Table.Includable
+	Include []string `json:"include"`
 	IRequest
+
+	// Sort key values of the row the requested page starts after, decoded
+	// from the next or prev cursor by Validate
+	after     []any
+	backwards bool
+	// Columns selected regardless of Fields, see Require
+	required []string
+	// SQL expressions of computed columns keyed by name, see Compute
+	computed map[string]string
 }
 
-// Generic cursors application
-func ApplyCursors[T any](r *Request, query *dbr.SelectStmt, response *T, getApiResponse func(*T) *ApiResponse) (*dbr.SelectStmt, *T) {
-	apiResp := getApiResponse(response)
+// StandardPageSize is the page size of requests without a page_size unless
+// DefaultPageSize is configured otherwise
+const StandardPageSize = 50
+
+// DefaultMaxPageSize is the largest page_size a request may ask for unless
+// MaxPageSize is configured otherwise
+const DefaultMaxPageSize = 100
+
+// DefaultPageSize is the page size of requests without a page_size
+var DefaultPageSize = StandardPageSize
+
+// MaxPageSize is the largest page_size a request may ask for
+var MaxPageSize = DefaultMaxPageSize
+
+// Validate checks the generic request fields against the queried table and
+// decodes the page cursor
+func (r *Request) Validate(v *Validator, table Table) {
+	r.normalize()
+
+	if r.PageSize != nil {
+		v.Range("page_size", *r.PageSize, 1, MaxPageSize)
+	}
+	r.Sort.Validate(v, slices.Concat(table.Sortable, slices.Sorted(maps.Keys(r.computed))))
+	for _, facet := range r.Facets {
+		v.OneOf("facets", facet, table.Facetable)
+	}
+	for _, field := range r.Fields {
+		v.OneOf("fields", field, table.Columns)
+	}
+	for _, relation := range r.Include {
+		v.OneOf("include", relation, table.Includable)
+	}
+
+	switch {
+	case r.Cursors.Next != nil && r.Cursors.Prev != nil:
+		v.Add("cursors", CodeInvalidCursor, "Only one of next and prev may be set.")
+	case r.Cursors.Next != nil:
+		var ok bool
+		r.after, ok = decodeCursor(r.Sort, *r.Cursors.Next)
+		v.Check(ok, "next", CodeInvalidCursor, "Cursor is malformed or belongs to a different sort.")
+	case r.Cursors.Prev != nil:
+		var ok bool
+		r.after, ok = decodeCursor(r.Sort, *r.Cursors.Prev)
+		r.backwards = true
+		v.Check(ok, "prev", CodeInvalidCursor, "Cursor is malformed or belongs to a different sort.")
+	}
+}
+
+// normalize trims and lowercases the column and relation names of the
+// request, which are matched against the lowercase names of the table
+func (r *Request) normalize() {
+	normalize := func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+
+	for i := range r.Fields {
+		r.Fields[i] = normalize(r.Fields[i])
+	}
+	for i := range r.Include {
+		r.Include[i] = normalize(r.Include[i])
+	}
+	for i := range r.Facets {
+		r.Facets[i] = normalize(r.Facets[i])
+	}
+	for i := range r.Sort {
+		r.Sort[i].Field = normalize(r.Sort[i].Field)
+		r.Sort[i].Dir = normalize(r.Sort[i].Dir)
+	}
+}
 
-	if r.PageSize == nil {
-		return query, response
+// Size returns the requested page size, or the default one
+func (r *Request) Size() int {
+	if r.PageSize != nil {
+		return *r.PageSize
 	}
+	return DefaultPageSize
+}
+
+// IncludesDeleted reports whether the request can return soft deleted records
+func (r *Request) IncludesDeleted() bool {
+	return r.IncludeDeleted || r.OnlyDeleted
+}
+
+// ApplyDeletedFilter excludes soft deleted records unless the request opts in
+func ApplyDeletedFilter(r *Request, query *dbr.SelectStmt) *dbr.SelectStmt {
+	switch {
+	case r.OnlyDeleted:
+		return query.Where(dbr.Eq("is_deleted", true))
+	case r.IncludeDeleted:
+		return query
+	default:
+		return query.Where(dbr.Or(dbr.Eq("is_deleted", nil), dbr.Eq("is_deleted", false)))
+	}
+}
+
+// ApplyCursors orders the query by the request sort and limits it to the page
+// after the next cursor or before the prev cursor. One row more than the page
+// size is selected to tell whether another page follows.
+func ApplyCursors(r *Request, table Table, query *dbr.SelectStmt) *dbr.SelectStmt {
+	query = r.Sort.Apply(query, r.backwards, r.expr)
+	if r.after != nil {
+		query = query.Where(r.Sort.After(r.after, r.backwards, r.expr))
+	}
+	return query.Limit(uint64(r.Size() + 1))
+}
 
-	apiResp.PageSize = r.PageSize
+// setCursors drops the extra row selected by ApplyCursors, puts the rows back
+// in sort order and sets the cursors of the pages around them
+func setCursors(r *Request, apiResp *ApiResponse, data interface{}) {
+	size := r.Size()
+	apiResp.PageSize = &size
 
-	if r.Cursors.Next == nil && r.Cursors.Prev == nil {
-		return query, response
+	rows := reflect.ValueOf(data).Elem()
+	more := rows.Len() > size
+	if more {
+		rows.Set(rows.Slice(0, size))
+	}
+	if rows.Len() == 0 {
+		return
 	}
 
-	var endId int
-	if r.Cursors.Next != nil && *r.Cursors.Next > 0 {
-		endId = *r.Cursors.Next + *r.PageSize
-		query = query.Where("id >= ?", *r.Cursors.Next).Where("id < ?", endId)
-		apiResp.Cursors.Next = &endId
-	} else if r.Cursors.Prev != nil && *r.Cursors.Prev > 0 {
-		endId = *r.Cursors.Prev - *r.PageSize
-		query = query.Where("id > ?", endId).Where("id <= ?", *r.Cursors.Prev)
-		apiResp.Cursors.Prev = &endId
+	if r.backwards {
+		swap := reflect.Swapper(rows.Interface())
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
 	}
 
-	return query, response
+	first := encodeCursor(r.Sort, rows.Index(0))
+	last := encodeCursor(r.Sort, rows.Index(rows.Len()-1))
+
+	// A page reached through a cursor has rows on the side it was reached
+	// from, the extra row tells whether there are rows on the other side
+	if r.backwards {
+		if more {
+			apiResp.Cursors.Prev = &first
+		}
+		apiResp.Cursors.Next = &last
+	} else {
+		if r.after != nil {
+			apiResp.Cursors.Prev = &first
+		}
+		if more {
+			apiResp.Cursors.Next = &last
+		}
+	}
+	apiResp.HasMore = apiResp.Cursors.Next != nil
 }
 
 // Generic Query function
 func Query[TReq any, TResp any](
 	req TReq,
 	db *dbr.Tx,
-	tableName string,
+	table Table,
 	newResponse func() *TResp,
 	getRequest func(TReq) *Request,
 	getApiResponse func(*TResp) *ApiResponse,
@@ -71,16 +248,19 @@ func Query[TReq any, TResp any](
 	// Validate filters
 	err := validateFilter()
 	if err != nil {
-		return nil, err
+		return nil, ValidationError(err)
 	}
 
 	response := newResponse()
 	baseReq := getRequest(req)
 
 	// Build query
-	query := db.Select("*").From(tableName)
-	query = applyFilters(query)
-	query, response = ApplyCursors(baseReq, query, response, getApiResponse)
+	filtered := func(columns ...any) *dbr.SelectStmt {
+		query := db.Select(columns...).From(table.Name)
+		query = applyFilters(query)
+		return ApplyDeletedFilter(baseReq, query)
+	}
+	query := ApplyCursors(baseReq, table, filtered(baseReq.columns()...))
 
 	// Load data
 	_, err = query.Load(getDataPtr(response))
@@ -88,5 +268,51 @@ func Query[TReq any, TResp any](
 		return nil, err
 	}
 
+	apiResp := getApiResponse(response)
+	apiResp.fields = baseReq.outputFields()
+	setCursors(baseReq, apiResp, getDataPtr(response))
+
+	err = count(baseReq, apiResp, filtered)
+	if err != nil {
+		return nil, err
+	}
+
 	return response, nil
 }
+
+// count runs the total and facet count queries the request asks for over the
+// rows matching its filters
+func count(r *Request, apiResp *ApiResponse, filtered func(columns ...any) *dbr.SelectStmt) error {
+	if r.IncludeTotal {
+		total := 0
+		err := filtered("COUNT(*)").LoadOne(&total)
+		if err != nil {
+			return err
+		}
+		apiResp.TotalCount = &total
+	}
+
+	for _, facet := range r.Facets {
+		// Facets are keyed by column name whatever case they were asked in
+		facet = strings.ToLower(strings.TrimSpace(facet))
+		if _, ok := apiResp.Facets[facet]; ok {
+			continue
+		}
+
+		counts := []FacetCount{}
+		_, err := filtered(facet+" AS value", "COUNT(*) AS count").
+			GroupBy(facet).
+			OrderBy(facet).
+			Load(&counts)
+		if err != nil {
+			return err
+		}
+
+		if apiResp.Facets == nil {
+			apiResp.Facets = map[string][]FacetCount{}
+		}
+		apiResp.Facets[facet] = counts
+	}
+
+	return nil
+}
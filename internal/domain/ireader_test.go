@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	_ "github.com/mattn/go-sqlite3"
+	"gotest.tools/v3/assert"
+)
+
+type countRow struct {
+	Id      int    `json:"id"`
+	Subject string `json:"subject"`
+}
+
+type countResponse struct {
+	ApiResponse
+	Data []*countRow
+}
+
+// newCountDb returns a transaction on an in memory database with a table of
+// three rows, one of them soft deleted
+func newCountDb(t *testing.T) *dbr.Tx {
+	conn, err := dbr.Open("sqlite3", ":memory:", nil)
+	assert.NilError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	tx, err := conn.NewSession(nil).Begin()
+	assert.NilError(t, err)
+	t.Cleanup(tx.RollbackUnlessCommitted)
+
+	_, err = tx.Exec("CREATE TABLE item (id INTEGER PRIMARY KEY, subject TEXT, is_deleted BOOLEAN)")
+	assert.NilError(t, err)
+	_, err = tx.Exec("INSERT INTO item (id, subject, is_deleted) VALUES (1, 'math', FALSE), (2, 'ela', FALSE), (3, 'math', FALSE), (4, 'math', TRUE)")
+	assert.NilError(t, err)
+
+	return tx
+}
+
+type CountTestCase struct {
+	name           string
+	request        Request
+	expectedTotal  *int
+	expectedFacets map[string][]FacetCount
+}
+
+func TestIReader_Count(t *testing.T) {
+	table := Table{
+		Name:      "item",
+		Columns:   []string{"id", "subject", "is_deleted"},
+		Sortable:  []string{"id", "subject"},
+		Facetable: []string{"subject"},
+	}
+	total := 3
+	pageSize := 1
+
+	testCases := []CountTestCase{
+		{
+			name:    "No counts",
+			request: Request{},
+		},
+		{
+			name:          "Total",
+			request:       Request{IncludeTotal: true, PageSize: &pageSize},
+			expectedTotal: &total,
+		},
+		{
+			name:    "Facets",
+			request: Request{Facets: []string{"subject"}},
+			expectedFacets: map[string][]FacetCount{
+				"subject": {{Value: "ela", Count: 1}, {Value: "math", Count: 2}},
+			},
+		},
+		{
+			name:          "Facet names ignore case",
+			request:       Request{IncludeTotal: true, Facets: []string{"Subject", "SUBJECT"}},
+			expectedTotal: &total,
+			expectedFacets: map[string][]FacetCount{
+				"subject": {{Value: "ela", Count: 1}, {Value: "math", Count: 2}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := newCountDb(t)
+
+			resp, err := Query(
+				&tc.request,
+				tx,
+				table,
+				func() *countResponse { return &countResponse{} },
+				func(req *Request) *Request { return req },
+				func(resp *countResponse) *ApiResponse { return &resp.ApiResponse },
+				func(resp *countResponse) interface{} { return &resp.Data },
+				func() error {
+					v := &Validator{}
+					tc.request.Validate(v, table)
+					return v.Err()
+				},
+				func(query *dbr.SelectStmt) *dbr.SelectStmt { return query },
+			)
+			assert.NilError(t, err)
+
+			assert.DeepEqual(t, resp.TotalCount, tc.expectedTotal)
+			assert.DeepEqual(t, resp.Facets, tc.expectedFacets)
+		})
+	}
+}
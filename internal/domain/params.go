@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"academic-api/internal/common"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseQuery fills the generic request fields from URL query parameters
+func (r *Request) ParseQuery(values url.Values) error {
+	var err error
+
+	r.Id, err = QueryInt(values, "id")
+	if err != nil {
+		return err
+	}
+
+	r.PageSize, err = QueryInt(values, "page_size")
+	if err != nil {
+		return err
+	}
+
+	r.Cursors.Next = QueryString(values, "next")
+	r.Cursors.Prev = QueryString(values, "prev")
+
+	r.Sort = QuerySort(values, "sort")
+
+	r.IncludeDeleted, err = QueryBool(values, "include_deleted")
+	if err != nil {
+		return err
+	}
+
+	r.OnlyDeleted, err = QueryBool(values, "only_deleted")
+	if err != nil {
+		return err
+	}
+
+	r.IncludeTotal, err = QueryBool(values, "include_total")
+	if err != nil {
+		return err
+	}
+
+	r.Facets = QueryList(values, "facets")
+	r.Fields = QueryList(values, "fields")
+	r.Include = QueryList(values, "include")
+
+	return nil
+}
+
+// QueryString returns the value of a query parameter, or nil when absent
+func QueryString(values url.Values, key string) *string {
+	if !values.Has(key) {
+		return nil
+	}
+	value := values.Get(key)
+	return &value
+}
+
+// QueryList returns the comma separated values of a query parameter, or nil
+// when absent
+func QueryList(values url.Values, key string) []string {
+	if !values.Has(key) {
+		return nil
+	}
+
+	var list []string
+	for value := range strings.SplitSeq(values.Get(key), ",") {
+		list = append(list, strings.TrimSpace(value))
+	}
+	return list
+}
+
+// QueryInt returns the integer value of a query parameter, or nil when absent
+func QueryInt(values url.Values, key string) (*int, error) {
+	if !values.Has(key) {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(values.Get(key))
+	if err != nil {
+		return nil, common.ErrBadRequest.Wrap(fmt.Errorf("Invalid integer for %s: %s", key, values.Get(key)))
+	}
+	return &value, nil
+}
+
+// QueryBool returns the boolean value of a query parameter, or false when absent
+func QueryBool(values url.Values, key string) (bool, error) {
+	if !values.Has(key) {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(values.Get(key))
+	if err != nil {
+		return false, common.ErrBadRequest.Wrap(fmt.Errorf("Invalid boolean for %s: %s", key, values.Get(key)))
+	}
+	return value, nil
+}
@@ -0,0 +1,273 @@
+package rawdata
+
+import (
+	"academic-api/internal/domain"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Scopes of the entity a raw document describes
+const (
+	ScopeSchool   = "school"
+	ScopeDistrict = "district"
+	ScopeState    = "state"
+)
+
+// Structures of a raw document
+const (
+	StructureJson = "json"
+	StructureCsv  = "csv"
+	StructureXml  = "xml"
+	StructureHtml = "html"
+	StructureXlsx = "xlsx"
+	StructureXls  = "xls"
+)
+
+var validScopes = []string{ScopeSchool, ScopeDistrict, ScopeState}
+var validStructures = []string{StructureJson, StructureCsv, StructureXml, StructureHtml, StructureXlsx, StructureXls}
+
+// Structures whose documents are stored base64 encoded
+var binaryStructures = []string{StructureXlsx, StructureXls}
+
+// RawData is a source document as fetched, which school reports are parsed from
+type RawData struct {
+	domain.Model
+	Scope string `json:"scope"`
+	// Where the document was fetched from, usually a URL
+	Source    string `json:"source"`
+	Structure string `json:"structure"`
+	// Document content, base64 encoded for binary structures
+	Actual string `json:"actual"`
+	// When the document was fetched from its source, the creation time unless given
+	FetchedAt domain.NullTime `json:"fetched_at"`
+	// Hex SHA-256 of the decoded content, set on create and update
+	ContentHash *string `json:"content_hash"`
+}
+
+func NewRawData(scope string, source string, structure string, actual string) *RawData {
+	return &RawData{
+		Scope:     scope,
+		Source:    source,
+		Structure: structure,
+		Actual:    actual,
+	}
+}
+
+// normalize trims and lowercases the vocabulary fields so they pass the
+// checks of the schema
+func (d *RawData) normalize() {
+	d.Scope = strings.ToLower(strings.TrimSpace(d.Scope))
+	d.Structure = strings.ToLower(strings.TrimSpace(d.Structure))
+}
+
+func (d *RawData) ValidateCreate() error {
+	v := &domain.Validator{}
+	d.validateFields(v)
+	return v.Err()
+}
+
+func (d *RawData) ValidateUpdate() error {
+	if d.IsDeleted.Bool {
+		return domain.ErrDeleted
+	}
+
+	v := &domain.Validator{}
+	v.Min("id", d.Id, 1)
+	d.validateFields(v)
+	return v.Err()
+}
+
+func (d *RawData) validateFields(v *domain.Validator) {
+	v.OneOf("scope", d.Scope, validScopes)
+	v.Required("source", d.Source)
+	v.OneOf("structure", d.Structure, validStructures)
+	v.Required("actual", d.Actual)
+
+	_, err := d.Content()
+	v.Check(err == nil, "actual", domain.CodeInvalidFormat, "Value must be base64 encoded for binary structures.")
+}
+
+// Content returns the document content, decoding binary structures
+func (d *RawData) Content() ([]byte, error) {
+	if !slices.Contains(binaryStructures, strings.ToLower(d.Structure)) {
+		return []byte(d.Actual), nil
+	}
+	return base64.StdEncoding.DecodeString(d.Actual)
+}
+
+// EncodeContent returns document content in the form stored in Actual
+func EncodeContent(structure string, content []byte) string {
+	if !slices.Contains(binaryStructures, strings.ToLower(structure)) {
+		return string(content)
+	}
+	return base64.StdEncoding.EncodeToString(content)
+}
+
+func (d *RawData) Create(db *dbr.Tx) error {
+	d.normalize()
+	err := d.ValidateCreate()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to validate raw data for create.")
+		return domain.ValidationError(err)
+	}
+
+	// Set timestamps
+	now := time.Now()
+	d.CreatedAt = domain.NullTime{
+		NullTime: sql.NullTime{Time: now, Valid: true},
+	}
+	d.UpdatedAt = domain.NullTime{
+		NullTime: sql.NullTime{Time: now, Valid: true},
+	}
+	d.IsDeleted = domain.NullBool{
+		NullBool: sql.NullBool{Bool: false, Valid: true},
+	}
+	if !d.FetchedAt.Valid {
+		d.FetchedAt = d.CreatedAt
+	}
+	d.ContentHash, err = d.hashContent()
+	if err != nil {
+		return domain.ValidationError(err)
+	}
+
+	err = db.InsertInto("raw_data").
+		Columns("scope", "source", "structure", "actual", "fetched_at", "content_hash", "created_at", "updated_at", "is_deleted").
+		Record(d).
+		Returning("id", "created_at", "updated_at").
+		Load(d)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert raw data to database.")
+		return domain.DbError(err)
+	}
+
+	return nil
+}
+
+func (d *RawData) Update(db *dbr.Tx) error {
+	d.normalize()
+	err := d.ValidateUpdate()
+	if err != nil {
+		return domain.ValidationError(err)
+	}
+	d.ContentHash, err = d.hashContent()
+	if err != nil {
+		return domain.ValidationError(err)
+	}
+
+	err = db.Update("raw_data").
+		Set("scope", d.Scope).
+		Set("source", d.Source).
+		Set("structure", d.Structure).
+		Set("actual", d.Actual).
+		Set("fetched_at", d.FetchedAt).
+		Set("content_hash", d.ContentHash).
+		Set("updated_at", time.Now()).
+		Where("id = ?", d.Id).
+		Returning("updated_at").
+		Load(d)
+
+	return domain.DbError(err)
+}
+
+// hashContent returns the hex SHA-256 of the document content
+func (d *RawData) hashContent() (*string, error) {
+	content, err := d.Content()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	return &hash, nil
+}
+
+// Hash returns the stored content hash, or computes it for documents stored
+// before hashes were recorded
+func (d *RawData) Hash() (string, error) {
+	if d.ContentHash != nil {
+		return *d.ContentHash, nil
+	}
+
+	hash, err := d.hashContent()
+	if err != nil {
+		return "", err
+	}
+	return *hash, nil
+}
+
+func (d *RawData) Delete(db *dbr.Tx) error {
+	if d.IsDeleted.Bool {
+		return domain.ErrDeleted
+	}
+
+	err := db.Update("raw_data").
+		Set("is_deleted", true).
+		Set("deleted_at", time.Now()).
+		Where("id = ?", d.Id).
+		Returning("is_deleted", "deleted_at").
+		Load(d)
+
+	return err
+}
+
+func (d *RawData) Restore(db *dbr.Tx) error {
+	if !d.IsDeleted.Bool {
+		return domain.ErrNotDeleted
+	}
+
+	err := db.Update("raw_data").
+		Set("is_deleted", false).
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Where("id = ?", d.Id).
+		Returning("is_deleted", "deleted_at", "updated_at").
+		Load(d)
+
+	return err
+}
+
+// Load returns the raw data with the given id, including soft deleted records
+func Load(db dbr.SessionRunner, id int) (*RawData, error) {
+	d := &RawData{}
+	err := db.Select("*").
+		From("raw_data").
+		Where("id = ?", id).
+		LoadOne(d)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// LoadLatest returns the last stored live document of the source
+func LoadLatest(db dbr.SessionRunner, source string) (*RawData, error) {
+	d := &RawData{}
+	err := db.Select("*").
+		From("raw_data").
+		Where("source = ?", source).
+		Where(dbr.Or(dbr.Eq("is_deleted", nil), dbr.Eq("is_deleted", false))).
+		OrderDesc("id").
+		Limit(1).
+		LoadOne(d)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,30 @@
+package rawdata
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type NormalizeTestCase struct {
+	name              string
+	data              *RawData
+	expectedScope     string
+	expectedStructure string
+}
+
+func TestRawData_Normalize(t *testing.T) {
+	testCases := []NormalizeTestCase{
+		{name: "Normalized", data: &RawData{Scope: "school", Structure: "json"}, expectedScope: "school", expectedStructure: "json"},
+		{name: "Upper case", data: &RawData{Scope: "School", Structure: "JSON"}, expectedScope: "school", expectedStructure: "json"},
+		{name: "Surrounding space", data: &RawData{Scope: " district ", Structure: "xlsx\n"}, expectedScope: "district", expectedStructure: "xlsx"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.data.normalize()
+			assert.Equal(t, tc.data.Scope, tc.expectedScope)
+			assert.Equal(t, tc.data.Structure, tc.expectedStructure)
+		})
+	}
+}
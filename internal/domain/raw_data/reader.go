@@ -0,0 +1,121 @@
+package rawdata
+
+import (
+	"academic-api/internal/domain"
+	"net/url"
+	"strings"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// Table is the raw_data table and the columns its queries may select, sort
+// and facet by
+var Table = domain.Table{
+	Name:      "raw_data",
+	Columns:   []string{"id", "scope", "source", "structure", "actual", "fetched_at", "content_hash", "is_deleted", "created_at", "updated_at", "deleted_at"},
+	Sortable:  []string{"id", "scope", "source", "structure"},
+	Facetable: []string{"scope", "source", "structure"},
+}
+
+type RawDataRequest struct {
+	domain.Request
+	Scope     *domain.Filter[string] `json:"scope"`
+	Source    *domain.Filter[string] `json:"source"`
+	Structure *domain.Filter[string] `json:"structure"`
+}
+
+type RawDataResponse struct {
+	domain.ApiResponse
+	Data []*RawData
+}
+
+func (r RawDataResponse) MarshalJSON() ([]byte, error) {
+	type response RawDataResponse
+	return domain.SparseJSON(response(r), &r.ApiResponse)
+}
+
+func (r *RawDataRequest) ParseQuery(values url.Values) error {
+	err := r.Request.ParseQuery(values)
+	if err != nil {
+		return err
+	}
+
+	r.Scope, err = domain.QueryStringFilter(values, "scope")
+	if err != nil {
+		return err
+	}
+
+	r.Source, err = domain.QueryStringFilter(values, "source")
+	if err != nil {
+		return err
+	}
+
+	r.Structure, err = domain.QueryStringFilter(values, "structure")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RawDataRequest) ValidateFilter() error {
+	r.normalizeFilter()
+
+	v := &domain.Validator{}
+	r.Request.Validate(v, Table)
+
+	r.Scope.Validate(v, "scope")
+	r.Source.Validate(v, "source")
+	r.Structure.Validate(v, "structure")
+
+	for _, scope := range r.Scope.Values() {
+		v.OneOf("scope", scope, validScopes)
+	}
+
+	for _, structure := range r.Structure.Values() {
+		v.OneOf("structure", structure, validStructures)
+	}
+
+	return v.Err()
+}
+
+// normalizeFilter trims and lowercases the vocabulary filters so they match
+// the values stored in the schema
+func (r *RawDataRequest) normalizeFilter() {
+	normalize := func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+
+	r.Scope.Map(normalize)
+	r.Structure.Map(normalize)
+}
+
+func (r *RawDataRequest) ApplyFilters(query *dbr.SelectStmt) *dbr.SelectStmt {
+	if r.Id != nil {
+		query = query.Where("id = ?", *r.Id)
+	}
+
+	query = r.Scope.Apply(query, "scope")
+	query = r.Source.Apply(query, "source")
+	query = r.Structure.Apply(query, "structure")
+
+	return query
+}
+
+func (r *RawDataRequest) ApplyCursors(query *dbr.SelectStmt) *dbr.SelectStmt {
+	return domain.ApplyCursors(&r.Request, Table, query)
+}
+
+func (r *RawDataRequest) Query(db *dbr.Tx) (*RawDataResponse, error) {
+	return domain.Query(
+		r,
+		db,
+		Table,
+		func() *RawDataResponse { return &RawDataResponse{} },
+		func(req *RawDataRequest) *domain.Request { return &req.Request },
+		func(resp *RawDataResponse) *domain.ApiResponse { return &resp.ApiResponse },
+		func(resp *RawDataResponse) interface{} { return &resp.Data },
+		r.ValidateFilter,
+		r.ApplyFilters,
+	)
+}
@@ -2,8 +2,11 @@ package school
 
 import (
 	"academic-api/internal/domain"
+	"academic-api/internal/domain/district"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gocraft/dbr/v2"
@@ -12,9 +15,17 @@ import (
 
 type School struct {
 	domain.Model
-	SchoolName   string `json:"school_name"`
-	StateCode    string `json:"state_code"`
+	SchoolName string `json:"school_name"`
+	StateCode  string `json:"state_code"`
+	DistrictId *int   `json:"district_id"`
+	// Name of the district, copied from the district when DistrictId is set
 	DistrictName string `json:"district_name"`
+	// Local education agency id assigned by the state
+	LeaId *string `json:"lea_id"`
+	// Search rank of the school, lower is better, set by queries with q
+	Rank *float64 `json:"rank,omitempty"`
+	// District of the school, embedded by queries with include=district
+	District *district.District `json:"district,omitempty" db:"-"`
 }
 
 func NewSchool(name string, state string, district string) *School {
@@ -26,30 +37,88 @@ func NewSchool(name string, state string, district string) *School {
 }
 
 func (s *School) ValidateCreate() error {
-	if s.SchoolName == "" {
-		return fmt.Errorf("Invalid school name.")
+	v := &domain.Validator{}
+	s.validateFields(v)
+	return v.Err()
+}
+
+func (s *School) ValidateUpdate() error {
+	if s.IsDeleted.Bool {
+		return domain.ErrDeleted
 	}
-	if len(s.StateCode) != 2 {
-		return fmt.Errorf("Invalid state code.")
+
+	v := &domain.Validator{}
+	v.Min("id", s.Id, 1)
+	s.validateFields(v)
+
+	// TODO: Prevent update for invalid timestamps
+	return v.Err()
+}
+
+func (s *School) validateFields(v *domain.Validator) {
+	v.Required("school_name", s.SchoolName)
+	v.Length("state_code", s.StateCode, 2)
+	if s.LeaId != nil {
+		v.Required("lea_id", *s.LeaId)
 	}
-	return nil
 }
 
-func (s *School) ValidateUpdate() error {
-	err := s.ValidateCreate()
+// linkDistrict checks that the district of the school exists in the same
+// state and copies its name to the school. A school with a district name but
+// no district id is linked to the district of that name, which is created
+// if the state has none.
+func (s *School) linkDistrict(db *dbr.Tx) error {
+	if s.DistrictId == nil {
+		return s.resolveDistrict(db)
+	}
+
+	v := &domain.Validator{}
+	d, err := district.Load(db, *s.DistrictId)
+	if errors.Is(err, domain.ErrNotFound) {
+		v.Add("district_id", domain.CodeInvalidReference, "District does not exist.")
+		return v.Err()
+	}
 	if err != nil {
 		return err
 	}
 
-	// TODO: Prevent update for invalid timestamps or attempts to update deleted records
-	return nil
+	v.Check(!d.IsDeleted.Bool, "district_id", domain.CodeInvalidReference, "District is deleted.")
+	v.Check(d.StateCode == s.StateCode, "state_code", domain.CodeInvalidChoice, fmt.Sprintf("Value must match the district state %s.", d.StateCode))
+	s.DistrictName = d.DistrictName
+	return v.Err()
+}
+
+// resolveDistrict sets the district of the school from its district name
+func (s *School) resolveDistrict(db *dbr.Tx) error {
+	name := strings.TrimSpace(s.DistrictName)
+	if name == "" {
+		return nil
+	}
+
+	d, err := district.LoadByName(db, s.StateCode, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		d = district.NewDistrict(name, s.StateCode, nil)
+		err = d.Create(db)
+	}
+	if err != nil {
+		return err
+	}
+
+	v := &domain.Validator{}
+	v.Check(!d.IsDeleted.Bool, "district_name", domain.CodeInvalidReference, "District is deleted.")
+	s.DistrictId = &d.Id
+	s.DistrictName = d.DistrictName
+	return v.Err()
 }
 
 func (s *School) Create(db *dbr.Tx) error {
 	err := s.ValidateCreate()
+	if err == nil {
+		err = s.linkDistrict(db)
+	}
 	if err != nil {
 		logrus.WithError(err).Error("Failed to validate school content for create.")
-		return err
+		return domain.ValidationError(err)
 	}
 
 	// Set timestamps
@@ -65,14 +134,14 @@ func (s *School) Create(db *dbr.Tx) error {
 	}
 
 	err = db.InsertInto("school").
-		Columns("school_name", "state_code", "district_name", "created_at", "updated_at", "is_deleted").
+		Columns("school_name", "state_code", "district_id", "district_name", "lea_id", "created_at", "updated_at", "is_deleted").
 		Record(s).
 		Returning("id", "created_at", "updated_at").
 		Load(s) // Load the ID and created_at back into the struct
 
 	if err != nil {
 		logrus.WithError(err).Error("Failed to insert school to database.")
-		return err
+		return domain.DbError(err)
 	}
 
 	return nil
@@ -81,28 +150,154 @@ func (s *School) Create(db *dbr.Tx) error {
 
 func (s *School) Update(db *dbr.Tx) error {
 	err := s.ValidateUpdate()
+	if err == nil {
+		err = s.linkDistrict(db)
+	}
 	if err != nil {
-		return err
+		return domain.ValidationError(err)
 	}
 
 	err = db.Update("school").
 		Set("school_name", s.SchoolName).
 		Set("state_code", s.StateCode).
+		Set("district_id", s.DistrictId).
 		Set("district_name", s.DistrictName).
+		Set("lea_id", s.LeaId).
 		Set("updated_at", time.Now()).
 		Where("id = ?", s.Id).
 		Returning("updated_at").
 		Load(s)
 
-	return err
+	return domain.DbError(err)
 }
 
 func (s *School) Delete(db *dbr.Tx) error {
+	if s.IsDeleted.Bool {
+		return domain.ErrDeleted
+	}
+
 	err := db.Update("school").
 		Set("is_deleted", true).
 		Set("deleted_at", time.Now()).
+		Where("id = ?", s.Id).
 		Returning("is_deleted", "deleted_at").
 		Load(s)
 
 	return err
 }
+
+func (s *School) Restore(db *dbr.Tx) error {
+	if !s.IsDeleted.Bool {
+		return domain.ErrNotDeleted
+	}
+
+	err := db.Update("school").
+		Set("is_deleted", false).
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Where("id = ?", s.Id).
+		Returning("is_deleted", "deleted_at", "updated_at").
+		Load(s)
+
+	return err
+}
+
+// Load returns the school with the given id, including soft deleted schools
+func Load(db dbr.SessionRunner, id int) (*School, error) {
+	s := &School{}
+	err := db.Select("*").
+		From("school").
+		Where("id = ?", id).
+		LoadOne(s)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// LoadByLea returns the school of a state with the given local education
+// agency id, including soft deleted schools
+func LoadByLea(db dbr.SessionRunner, stateCode string, leaId string) (*School, error) {
+	s := &School{}
+	err := db.Select("*").
+		From("school").
+		Where("state_code = ?", stateCode).
+		Where("lea_id = ?", leaId).
+		LoadOne(s)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// LeaIds returns the local education agency ids of the schools of a state
+// that have one, excluding soft deleted schools
+func LeaIds(db dbr.SessionRunner, stateCode string) ([]string, error) {
+	var leaIds []string
+	_, err := db.Select("lea_id").
+		From("school").
+		Where("state_code = ?", stateCode).
+		Where("lea_id IS NOT NULL").
+		Where(dbr.Or(dbr.Eq("is_deleted", nil), dbr.Eq("is_deleted", false))).
+		OrderBy("lea_id").
+		Load(&leaIds)
+	return leaIds, err
+}
+
+// LoadMany returns the schools with the given ids keyed by id, including soft
+// deleted schools
+func LoadMany(db dbr.SessionRunner, ids []int) (map[int]*School, error) {
+	schools := map[int]*School{}
+	if len(ids) == 0 {
+		return schools, nil
+	}
+
+	var rows []*School
+	_, err := db.Select("*").
+		From("school").
+		Where(dbr.Eq("id", ids)).
+		Load(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range rows {
+		schools[s.Id] = s
+	}
+	return schools, nil
+}
+
+// DeletedBefore returns the ids of schools soft deleted before the given time
+func DeletedBefore(db dbr.SessionRunner, before time.Time) ([]int, error) {
+	var ids []int
+	_, err := db.Select("id").
+		From("school").
+		Where("is_deleted = ?", true).
+		Where("deleted_at < ?", before).
+		Load(&ids)
+	return ids, err
+}
+
+// Purge permanently deletes the schools with the given ids. Their school
+// reports must be purged first.
+func Purge(db *dbr.Tx, ids []int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := db.DeleteFrom("school").
+		Where("id IN ?", ids).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,15 +2,35 @@ package school
 
 import (
 	"academic-api/internal/domain"
-	"fmt"
+	"academic-api/internal/domain/district"
+	"net/url"
+	"slices"
 
 	"github.com/gocraft/dbr/v2"
 )
 
+// Table is the school table, the columns its queries may select, sort and
+// facet by and the relations they may embed
+var Table = domain.Table{
+	Name:       "school",
+	Columns:    []string{"id", "school_name", "state_code", "district_id", "district_name", "lea_id", "is_deleted", "created_at", "updated_at", "deleted_at"},
+	Sortable:   []string{"id", "school_name", "state_code", "district_name"},
+	Facetable:  []string{"state_code", "district_id", "district_name"},
+	Includable: []string{"district"},
+}
+
 type SchoolRequest struct {
 	domain.Request
-	StateCode    *string `json:"state_code"`
-	DistrictName *string `json:"district_name"`
+	StateCode    *domain.Filter[string] `json:"state_code"`
+	DistrictId   *domain.Filter[int]    `json:"district_id"`
+	DistrictName *domain.Filter[string] `json:"district_name"`
+	SchoolName   *domain.Filter[string] `json:"school_name"`
+	LeaId        *domain.Filter[string] `json:"lea_id"`
+	// Search school and district names, results are ranked by relevance
+	Q *string `json:"q"`
+
+	// Ids of the schools matching Q
+	matches []int
 }
 
 type SchoolResponse struct {
@@ -18,41 +38,116 @@ type SchoolResponse struct {
 	Data []*School
 }
 
-func (r *SchoolRequest) ValidateFilter() error {
-	if r.StateCode != nil && len(*r.StateCode) != 2 {
-		return fmt.Errorf("State code not valid.")
+func (r SchoolResponse) MarshalJSON() ([]byte, error) {
+	type response SchoolResponse
+	return domain.SparseJSON(response(r), &r.ApiResponse)
+}
+
+func (r *SchoolRequest) ParseQuery(values url.Values) error {
+	err := r.Request.ParseQuery(values)
+	if err != nil {
+		return err
+	}
+
+	r.StateCode, err = domain.QueryStringFilter(values, "state_code")
+	if err != nil {
+		return err
 	}
 
+	r.DistrictId, err = domain.QueryIntFilter(values, "district_id")
+	if err != nil {
+		return err
+	}
+
+	r.DistrictName, err = domain.QueryStringFilter(values, "district_name")
+	if err != nil {
+		return err
+	}
+
+	r.SchoolName, err = domain.QueryStringFilter(values, "school_name")
+	if err != nil {
+		return err
+	}
+
+	r.LeaId, err = domain.QueryStringFilter(values, "lea_id")
+	if err != nil {
+		return err
+	}
+
+	r.Q = domain.QueryString(values, "q")
+
 	return nil
 }
 
-func (r *SchoolRequest) ApplyFilters(query *dbr.SelectStmt) *dbr.SelectStmt {
-	if r.Id != nil {
-		query = query.Where("id = ?", *r.Id)
+func (r *SchoolRequest) ValidateFilter() error {
+	v := &domain.Validator{}
+	r.Request.Validate(v, Table)
+	if r.Includes("district") {
+		r.Require("district_id")
 	}
 
-	if r.StateCode != nil {
-		query = query.Where("state_code = ?", *r.StateCode)
+	r.StateCode.Validate(v, "state_code")
+	r.DistrictId.Validate(v, "district_id")
+	r.DistrictName.Validate(v, "district_name")
+	r.SchoolName.Validate(v, "school_name")
+	r.LeaId.Validate(v, "lea_id")
+
+	for _, stateCode := range r.StateCode.Values() {
+		v.Length("state_code", stateCode, 2)
+	}
+
+	if r.Q != nil {
+		v.Check(len(searchTerms(*r.Q)) > 0, "q", domain.CodeRequired, "Search must contain a word.")
 	}
 
-	if r.DistrictName != nil {
-		query = query.Where("district_name = ?", *r.DistrictName)
+	return v.Err()
+}
+
+func (r *SchoolRequest) ApplyFilters(query *dbr.SelectStmt) *dbr.SelectStmt {
+	query = r.applyFieldFilters(query)
+
+	if r.Q != nil {
+		query = query.Where(dbr.Eq("id", r.matches))
 	}
 
 	return query
 }
 
-func (r *SchoolRequest) ApplyCursors(query *dbr.SelectStmt, response *SchoolResponse) (*dbr.SelectStmt, *SchoolResponse) {
-	return domain.ApplyCursors(&r.Request, query, response, func(resp *SchoolResponse) *domain.ApiResponse {
-		return &resp.ApiResponse
-	})
+// applyFieldFilters applies every filter but the search
+func (r *SchoolRequest) applyFieldFilters(query *dbr.SelectStmt) *dbr.SelectStmt {
+	if r.Id != nil {
+		query = query.Where("id = ?", *r.Id)
+	}
+
+	query = r.StateCode.Apply(query, "state_code")
+	query = r.DistrictId.Apply(query, "district_id")
+	query = r.DistrictName.Apply(query, "district_name")
+	query = r.SchoolName.Apply(query, "school_name")
+	query = r.LeaId.Apply(query, "lea_id")
+
+	return query
+}
+
+// filterSchools restricts a query of the school table to the schools the
+// request may return, before any search
+func (r *SchoolRequest) filterSchools(query *dbr.SelectStmt) *dbr.SelectStmt {
+	return domain.ApplyDeletedFilter(&r.Request, r.applyFieldFilters(query))
+}
+
+func (r *SchoolRequest) ApplyCursors(query *dbr.SelectStmt) *dbr.SelectStmt {
+	return domain.ApplyCursors(&r.Request, Table, query)
 }
 
 func (r *SchoolRequest) Query(db *dbr.Tx) (*SchoolResponse, error) {
-	return domain.Query(
+	err := r.search(db)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := domain.Query(
 		r,
 		db,
-		"school",
+		Table,
 		func() *SchoolResponse { return &SchoolResponse{} },
 		func(req *SchoolRequest) *domain.Request { return &req.Request },
 		func(resp *SchoolResponse) *domain.ApiResponse { return &resp.ApiResponse },
@@ -60,4 +155,76 @@ func (r *SchoolRequest) Query(db *dbr.Tx) (*SchoolResponse, error) {
 		r.ValidateFilter,
 		r.ApplyFilters,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Includes("district") {
+		err = includeDistricts(db, resp.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// includeDistricts embeds the district in each school that has one, loading
+// every district of the page in one query
+func includeDistricts(db *dbr.Tx, schools []*School) error {
+	ids := make([]int, 0, len(schools))
+	for _, s := range schools {
+		if s.DistrictId != nil {
+			ids = append(ids, *s.DistrictId)
+		}
+	}
+	slices.Sort(ids)
+
+	districts, err := district.LoadMany(db, slices.Compact(ids))
+	if err != nil {
+		return err
+	}
+
+	for _, s := range schools {
+		if s.DistrictId != nil {
+			s.District = districts[*s.DistrictId]
+		}
+	}
+	return nil
+}
+
+// search finds the schools matching Q among the schools the filters allow and adds their rank as a column, which
+// results are sorted by unless the request sorts them otherwise
+func (r *SchoolRequest) search(db *dbr.Tx) error {
+	if r.Q == nil {
+		return nil
+	}
+	terms := searchTerms(*r.Q)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Rank is sortable before it is computed so the filters, which restrict
+	// the search, can be validated first
+	r.Compute("rank", rankExpr(nil))
+	if len(r.Sort) == 0 {
+		r.Sort = domain.Sort{{Field: "rank", Dir: domain.SortAsc}}
+	}
+	err := r.ValidateFilter()
+	if err != nil {
+		return domain.ValidationError(err)
+	}
+
+	hits, err := search(db, terms, r.filterSchools)
+	if err != nil {
+		return err
+	}
+
+	r.matches = make([]int, len(hits))
+	for i, hit := range hits {
+		r.matches[i] = hit.Id
+	}
+
+	r.Compute("rank", rankExpr(hits))
+	return nil
 }
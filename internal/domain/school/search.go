@@ -0,0 +1,197 @@
+package school
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Most schools a search matches, best ranked first. The limit applies after
+// the filters of the request.
+const maxSearchResults = 1000
+
+// ErrNoSearchIndex is returned when SQLite lacks the FTS5 module that the
+// triggers on the school table write the search index with
+var ErrNoSearchIndex = errors.New("SQLite was built without FTS5, build with -tags sqlite_fts5.")
+
+// CheckSearchIndex returns ErrNoSearchIndex unless SQLite can write the
+// school search index. Every binary that writes schools must check it, as
+// school inserts, updates and deletes fail without FTS5.
+func CheckSearchIndex(db dbr.SessionRunner) error {
+	var fts5 bool
+	err := db.SelectBySql("SELECT sqlite_compileoption_used('ENABLE_FTS5')").LoadOne(&fts5)
+	if err != nil {
+		return err
+	}
+	if !fts5 {
+		return ErrNoSearchIndex
+	}
+	return nil
+}
+
+// searchHit is a school matched by a search and its rank, lower is better
+type searchHit struct {
+	Id   int
+	Rank float64
+}
+
+// searchTerms splits a search into lowercase words
+func searchTerms(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// search ranks the schools whose name or district matches every term, among
+// the schools of the school table that filter keeps. Terms match words by
+// prefix through the full-text index. When nothing matches, terms may instead
+// be a few typos away from a word.
+func search(db *dbr.Tx, terms []string, filter func(*dbr.SelectStmt) *dbr.SelectStmt) ([]searchHit, error) {
+	hits, err := fullTextSearch(db, terms, filter)
+	if err != nil {
+		logrus.WithError(err).Warn("Full-text school search failed, falling back to fuzzy search.")
+	}
+	if len(hits) > 0 {
+		return hits, nil
+	}
+
+	return fuzzySearch(db, terms, filter)
+}
+
+// fullTextSearch ranks matches by the bm25 rank of the school_search index
+func fullTextSearch(db *dbr.Tx, terms []string, filter func(*dbr.SelectStmt) *dbr.SelectStmt) ([]searchHit, error) {
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = fmt.Sprintf(`"%s"*`, term)
+	}
+
+	var hits []searchHit
+	_, err := db.Select("rowid AS id", "rank").
+		From("school_search").
+		Where("school_search MATCH ?", strings.Join(phrases, " ")).
+		Where("rowid IN ?", filter(db.Select("id").From("school"))).
+		OrderBy("rank").
+		Limit(maxSearchResults).
+		Load(&hits)
+	return hits, err
+}
+
+// fuzzySearch ranks matches by the number of edits between the terms and the
+// words they match. It compares the terms with every school the filter keeps,
+// so its cost grows with the filtered school table. It only runs when the
+// full-text index matches nothing.
+func fuzzySearch(db *dbr.Tx, terms []string, filter func(*dbr.SelectStmt) *dbr.SelectStmt) ([]searchHit, error) {
+	var schools []*School
+	_, err := filter(db.Select("id", "school_name", "district_name").From("school")).
+		Load(&schools)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []searchHit
+	for _, s := range schools {
+		words := searchTerms(s.SchoolName + " " + s.DistrictName)
+		if edits, ok := fuzzyMatch(terms, words); ok {
+			hits = append(hits, searchHit{Id: s.Id, Rank: float64(edits)})
+		}
+	}
+
+	slices.SortFunc(hits, func(a, b searchHit) int {
+		return cmp.Or(cmp.Compare(a.Rank, b.Rank), cmp.Compare(a.Id, b.Id))
+	})
+	if len(hits) > maxSearchResults {
+		hits = hits[:maxSearchResults]
+	}
+	return hits, nil
+}
+
+// fuzzyMatch returns the edits needed for every term to match a word, either
+// whole or by a prefix of about the term's length, and whether each term is
+// within its typo allowance
+func fuzzyMatch(terms []string, words []string) (int, bool) {
+	total := 0
+	for _, term := range terms {
+		best := -1
+		length := len([]rune(term))
+		for _, word := range words {
+			edits := min(
+				levenshtein(term, word),
+				levenshtein(term, prefix(word, length-1)),
+				levenshtein(term, prefix(word, length)),
+				levenshtein(term, prefix(word, length+1)),
+			)
+			if best < 0 || edits < best {
+				best = edits
+			}
+		}
+		if best < 0 || best > typoAllowance(term) {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// typoAllowance is the number of edits a term may be away from a word
+func typoAllowance(term string) int {
+	switch length := len([]rune(term)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// prefix returns the first n runes of s
+func prefix(s string, n int) string {
+	runes := []rune(s)
+	return string(runes[:min(n, len(runes))])
+}
+
+// levenshtein returns the number of single rune insertions, deletions or
+// substitutions that turn a into b
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// rankExpr returns a SQL expression giving the rank of each hit by school id
+func rankExpr(hits []searchHit) string {
+	if len(hits) == 0 {
+		return "NULL"
+	}
+
+	var expr strings.Builder
+	expr.WriteString("CASE id")
+	for _, hit := range hits {
+		fmt.Fprintf(&expr, " WHEN %d THEN %s", hit.Id, strconv.FormatFloat(hit.Rank, 'g', -1, 64))
+	}
+	expr.WriteString(" END")
+	return expr.String()
+}
@@ -0,0 +1,55 @@
+package school
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type LevenshteinTestCase struct {
+	name          string
+	a             string
+	b             string
+	expectedEdits int
+}
+
+func TestSearch_Levenshtein(t *testing.T) {
+	testCases := []LevenshteinTestCase{
+		{name: "Equal", a: "lincoln", b: "lincoln", expectedEdits: 0},
+		{name: "Missing letter", a: "lincon", b: "lincoln", expectedEdits: 1},
+		{name: "Swapped letters", a: "linlcon", b: "lincoln", expectedEdits: 2},
+		{name: "Empty", a: "", b: "high", expectedEdits: 4},
+		{name: "Multibyte runes", a: "élan", b: "elan", expectedEdits: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, levenshtein(tc.a, tc.b), tc.expectedEdits)
+		})
+	}
+}
+
+type FuzzyMatchTestCase struct {
+	name          string
+	q             string
+	text          string
+	expectedEdits int
+	expectedOk    bool
+}
+
+func TestSearch_FuzzyMatch(t *testing.T) {
+	testCases := []FuzzyMatchTestCase{
+		{name: "Typo", q: "lincon", text: "Lincoln Elementary", expectedEdits: 1, expectedOk: true},
+		{name: "Typo in prefix", q: "elemnt", text: "Lincoln Elementary", expectedEdits: 1, expectedOk: true},
+		{name: "Every term must match", q: "lincon high", text: "Lincoln Elementary"},
+		{name: "Short terms allow no typos", q: "hgh", text: "Lincoln High"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits, ok := fuzzyMatch(searchTerms(tc.q), searchTerms(tc.text))
+			assert.Equal(t, ok, tc.expectedOk)
+			assert.Equal(t, edits, tc.expectedEdits)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package schoolreport
+
+import (
+	"academic-api/internal/common"
+	"errors"
+)
+
+// Bulk row statuses. Upsert requests report the Upsert outcome of each row
+// instead of BulkStatusCreated.
+const (
+	BulkStatusCreated    = "created"
+	BulkStatusFailed     = "failed"
+	BulkStatusRolledBack = "rolled_back"
+)
+
+// BulkRowResult reports the outcome of one row of a bulk request
+type BulkRowResult struct {
+	Index  int                `json:"index"`
+	Status string             `json:"status"`
+	Id     *int               `json:"id,omitempty"`
+	Error  *string            `json:"error,omitempty"`
+	Errors common.FieldErrors `json:"errors,omitempty"`
+}
+
+// Fail marks the row as failed with err, listing its field errors if any
+func (r *BulkRowResult) Fail(err error) {
+	msg := err.Error()
+	r.Status = BulkStatusFailed
+	r.Id = nil
+	r.Error = &msg
+
+	var fieldErrs common.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		r.Errors = fieldErrs
+	}
+}
+
+type BulkResponse struct {
+	Atomic    bool             `json:"atomic"`
+	Upsert    bool             `json:"upsert"`
+	Total     int              `json:"total"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	Failed    int              `json:"failed"`
+	Results   []*BulkRowResult `json:"results"`
+}
+
+// Tally counts the rows of the response by status
+func (r *BulkResponse) Tally() {
+	r.Total = len(r.Results)
+	r.Created = 0
+	r.Updated = 0
+	r.Unchanged = 0
+	r.Failed = 0
+	for _, result := range r.Results {
+		switch result.Status {
+		case BulkStatusCreated, UpsertInserted:
+			r.Created++
+		case UpsertUpdated:
+			r.Updated++
+		case UpsertUnchanged:
+			r.Unchanged++
+		case BulkStatusFailed:
+			r.Failed++
+		}
+	}
+}
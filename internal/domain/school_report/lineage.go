@@ -0,0 +1,83 @@
+package schoolreport
+
+import (
+	"academic-api/internal/domain"
+	rawdata "academic-api/internal/domain/raw_data"
+	"errors"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// Source is the raw document a school report was parsed from, without its
+// content
+type Source struct {
+	Id        int    `json:"id"`
+	Scope     string `json:"scope"`
+	Source    string `json:"source"`
+	Structure string `json:"structure"`
+	// When the document was fetched from its source
+	FetchedAt domain.NullTime `json:"fetched_at"`
+	// Hex SHA-256 of the content, computed from the content when not stored
+	ContentHash string          `json:"content_hash"`
+	IsDeleted   domain.NullBool `json:"is_deleted"`
+}
+
+// Lineage traces a school report back to the raw document it was parsed from
+// and the other reports parsed from the same document
+type Lineage struct {
+	Report *SchoolReport `json:"report"`
+	// Raw document of the report, null when it no longer exists
+	RawData *Source `json:"raw_data"`
+	// Page of the other reports of the raw document
+	Reports *SchoolReportResponse `json:"reports"`
+}
+
+// Lineage returns the lineage of the school report with the given id. The
+// request filters, sorts and pages the other reports of its raw document.
+func (r *SchoolReportRequest) Lineage(db *dbr.Tx, id int) (*Lineage, error) {
+	report, err := Load(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if report.IsDeleted.Bool && !r.IncludesDeleted() {
+		return nil, domain.ErrNotFound
+	}
+
+	lineage := &Lineage{Report: report}
+	data, err := rawdata.Load(db, report.DataId)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if data != nil {
+		lineage.RawData, err = newSource(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.DataId = domain.Eq(report.DataId)
+	r.exceptId = &report.Id
+	lineage.Reports, err = r.Query(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return lineage, nil
+}
+
+func newSource(data *rawdata.RawData) (*Source, error) {
+	hash, err := data.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Source{
+		Id:          data.Id,
+		Scope:       data.Scope,
+		Source:      data.Source,
+		Structure:   data.Structure,
+		FetchedAt:   data.FetchedAt,
+		ContentHash: hash,
+		IsDeleted:   data.IsDeleted,
+	}, nil
+}
@@ -2,9 +2,10 @@ package schoolreport
 
 import (
 	"academic-api/internal/domain"
+	rawdata "academic-api/internal/domain/raw_data"
+	"academic-api/internal/domain/school"
 	"database/sql"
-	"fmt"
-	"slices"
+	"errors"
 	"strings"
 	"time"
 
@@ -13,25 +14,41 @@ import (
 )
 
 var validSubjects = []string{"ela", "math"}
-var validGradeLevels = []string{"3", "4", "5", "6", "7", "8", "3-8"}
+var validGradeLevels = []string{"3", "4", "5", "6", "7", "8", "3-8", "all"}
 var validDemographicGroups = []string{"all", "black", "hispanic", "economically_disadvantaged"}
 
+// Earliest academic year accepted by filters
+const minAcademicYear = 1990
+
+// Upsert outcomes
+const (
+	UpsertInserted  = "inserted"
+	UpsertUpdated   = "updated"
+	UpsertUnchanged = "unchanged"
+)
+
+// UpsertResponse is a school report along with the outcome of its upsert
+type UpsertResponse struct {
+	Status string `json:"status"`
+	*SchoolReport
+}
+
 type SchoolReport struct {
 	domain.Model
-	SchoolId         int    `json:"school_id"`
-	DataId           int    `json:"data_id"`
-	AcademicYear     int    `json:"academic_year"`
-	Subject          string `json:"subject"`
-	GradeLevel       string `json:"grade_level"`
-	DemographicGroup string `json:"demographic_group"`
-	NTested          int    `json:"n_tested"`
-	NProficient      int    `json:"n_proficient"`
-	PctProficient    int    `json:"pct_proficient"`
+	SchoolId         int     `json:"school_id"`
+	DataId           int     `json:"data_id"`
+	AcademicYear     int     `json:"academic_year"`
+	Subject          string  `json:"subject"`
+	GradeLevel       string  `json:"grade_level"`
+	DemographicGroup string  `json:"demographic_group"`
+	NTested          int     `json:"n_tested"`
+	NProficient      int     `json:"n_proficient"`
+	PctProficient    float64 `json:"pct_proficient"`
+	// Parent school, embedded by queries with include=school
+	School *school.School `json:"school,omitempty" db:"-"`
 }
 
 func NewSchoolReport(schoolId int, dataId int, academicYear int, subject string, gradeLevel string, demographicGroup string, nTested int, nProficient int) *SchoolReport {
-	pctProficient := nProficient / nTested * 100.00
-
 	return &SchoolReport{
 		SchoolId:         schoolId,
 		DataId:           dataId,
@@ -41,52 +58,81 @@ func NewSchoolReport(schoolId int, dataId int, academicYear int, subject string,
 		DemographicGroup: demographicGroup,
 		NTested:          nTested,
 		NProficient:      nProficient,
-		PctProficient:    pctProficient,
+		PctProficient:    PctProficient(nTested, nProficient),
 	}
 }
 
-func (r *SchoolReport) ValidateCreate() error {
-	if !slices.Contains(validSubjects, strings.ToLower(r.Subject)) {
-		return fmt.Errorf("Invalid subject for report: %s", r.Subject)
+// PctProficient returns the percentage of tested students who were
+// proficient. It divides as floats, integer division truncated every report
+// with fewer proficient than tested students to 0.
+func PctProficient(nTested int, nProficient int) float64 {
+	if nTested <= 0 {
+		return 0
 	}
+	return float64(nProficient) / float64(nTested) * 100
+}
 
-	if !slices.Contains(validGradeLevels, r.GradeLevel) {
-		return fmt.Errorf("Invalid grade level for report: %s", r.GradeLevel)
-	}
+// normalize trims and lowercases the vocabulary fields so reports sent with
+// another case share the key of the stored report
+func (r *SchoolReport) normalize() {
+	r.Subject = strings.ToLower(strings.TrimSpace(r.Subject))
+	r.GradeLevel = strings.ToLower(strings.TrimSpace(r.GradeLevel))
+	r.DemographicGroup = strings.ToLower(strings.TrimSpace(r.DemographicGroup))
+}
 
-	if !slices.Contains(validDemographicGroups, strings.ToLower(r.DemographicGroup)) {
-		return fmt.Errorf("Invalid demographic group: %s", r.DemographicGroup)
-	}
+func (r *SchoolReport) ValidateCreate() error {
+	v := &domain.Validator{}
+	r.validateFields(v)
+	return v.Err()
+}
 
-	if r.NTested < 0 {
-		return fmt.Errorf("Invalid N tested: %d", r.NTested)
+func (r *SchoolReport) ValidateUpdate() error {
+	if r.IsDeleted.Bool {
+		return domain.ErrDeleted
 	}
 
-	if r.NProficient < 0 {
-		return fmt.Errorf("Invalid N proficient: %d", r.NProficient)
-	}
+	v := &domain.Validator{}
+	v.Min("id", r.Id, 1)
+	r.validateFields(v)
 
-	if r.NProficient > r.NTested {
-		return fmt.Errorf("N proficient cannot exceed N tested.")
-	}
+	// TODO: validate other updates
+	return v.Err()
+}
 
-	return nil
+func (r *SchoolReport) validateFields(v *domain.Validator) {
+	v.OneOf("subject", r.Subject, validSubjects)
+	v.OneOf("grade_level", r.GradeLevel, validGradeLevels)
+	v.OneOf("demographic_group", r.DemographicGroup, validDemographicGroups)
+	v.Min("n_tested", r.NTested, 0)
+	v.Min("n_proficient", r.NProficient, 0)
+	v.Check(r.NProficient <= r.NTested, "n_proficient", domain.CodeOutOfRange, "N proficient cannot exceed N tested.")
 }
 
-func (r *SchoolReport) ValidateUpdate() error {
-	err := r.ValidateCreate()
+// checkRawData checks that the raw data the report is parsed from exists
+func (r *SchoolReport) checkRawData(db *dbr.Tx) error {
+	v := &domain.Validator{}
+	data, err := rawdata.Load(db, r.DataId)
+	if errors.Is(err, domain.ErrNotFound) {
+		v.Add("data_id", domain.CodeInvalidReference, "Raw data does not exist.")
+		return v.Err()
+	}
 	if err != nil {
 		return err
 	}
 
-	// TODO: validate other updates
-	return nil
+	v.Check(!data.IsDeleted.Bool, "data_id", domain.CodeInvalidReference, "Raw data is deleted.")
+	return v.Err()
 }
 
 func (r *SchoolReport) Create(db *dbr.Tx) error {
+	r.normalize()
 	err := r.ValidateCreate()
+	if err == nil {
+		err = r.checkRawData(db)
+	}
 	if err != nil {
 		logrus.WithError(err).Error("Failed to validate school report for create.")
+		return domain.ValidationError(err)
 	}
 
 	// Set timestamps
@@ -102,7 +148,7 @@ func (r *SchoolReport) Create(db *dbr.Tx) error {
 	}
 
 	// Recalculate pct proficient
-	r.PctProficient = r.NProficient / r.NTested * 100
+	r.PctProficient = PctProficient(r.NTested, r.NProficient)
 
 	err = db.InsertInto("school_report").
 		Columns(
@@ -127,18 +173,25 @@ func (r *SchoolReport) Create(db *dbr.Tx) error {
 		).Load(r)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to insert school report to database.")
-		return err
+		return domain.DbError(err)
 	}
 
 	return nil
 }
 
 func (r *SchoolReport) Update(db *dbr.Tx) error {
+	r.normalize()
 	err := r.ValidateUpdate()
+	if err == nil {
+		err = r.checkRawData(db)
+	}
 	if err != nil {
-		return err
+		return domain.ValidationError(err)
 	}
 
+	// Recalculate pct proficient
+	r.PctProficient = PctProficient(r.NTested, r.NProficient)
+
 	err = db.Update("school_report").
 		Set("school_id", r.SchoolId).
 		Set("data_id", r.DataId).
@@ -154,15 +207,145 @@ func (r *SchoolReport) Update(db *dbr.Tx) error {
 		Returning("updated_at").
 		Load(r)
 
-	return err
+	return domain.DbError(err)
+}
+
+// Upsert creates the report, or updates the counts of the report that shares
+// its school, academic year, subject, grade level and demographic group. It
+// returns whether the report was inserted, updated or unchanged.
+func (r *SchoolReport) Upsert(db *dbr.Tx) (string, error) {
+	r.normalize()
+	err := r.ValidateCreate()
+	if err != nil {
+		return "", domain.ValidationError(err)
+	}
+
+	existing, err := LoadByKey(db, r.SchoolId, r.AcademicYear, r.Subject, r.GradeLevel, r.DemographicGroup)
+	if errors.Is(err, domain.ErrNotFound) {
+		err = r.Create(db)
+		if err != nil {
+			return "", err
+		}
+		return UpsertInserted, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if existing.IsDeleted.Bool {
+		return "", domain.ErrDeleted
+	}
+
+	if existing.DataId == r.DataId && existing.NTested == r.NTested && existing.NProficient == r.NProficient {
+		*r = *existing
+		return UpsertUnchanged, nil
+	}
+
+	existing.DataId = r.DataId
+	existing.NTested = r.NTested
+	existing.NProficient = r.NProficient
+	err = existing.Update(db)
+	if err != nil {
+		return "", err
+	}
+
+	*r = *existing
+	return UpsertUpdated, nil
 }
 
 func (r *SchoolReport) Delete(db *dbr.Tx) error {
+	if r.IsDeleted.Bool {
+		return domain.ErrDeleted
+	}
+
 	err := db.Update("school_report").
 		Set("is_deleted", true).
 		Set("deleted_at", time.Now()).
+		Where("id = ?", r.Id).
 		Returning("is_deleted", "deleted_at").
 		Load(r)
 
 	return err
 }
+
+func (r *SchoolReport) Restore(db *dbr.Tx) error {
+	if !r.IsDeleted.Bool {
+		return domain.ErrNotDeleted
+	}
+
+	err := db.Update("school_report").
+		Set("is_deleted", false).
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Where("id = ?", r.Id).
+		Returning("is_deleted", "deleted_at", "updated_at").
+		Load(r)
+
+	return err
+}
+
+// Load returns the school report with the given id, including soft deleted reports
+func Load(db dbr.SessionRunner, id int) (*SchoolReport, error) {
+	r := &SchoolReport{}
+	err := db.Select("*").
+		From("school_report").
+		Where("id = ?", id).
+		LoadOne(r)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// LoadByKey returns the school report with the given natural key, including
+// soft deleted reports
+func LoadByKey(db dbr.SessionRunner, schoolId int, academicYear int, subject string, gradeLevel string, demographicGroup string) (*SchoolReport, error) {
+	r := &SchoolReport{}
+	err := db.Select("*").
+		From("school_report").
+		Where("school_id = ?", schoolId).
+		Where("academic_year = ?", academicYear).
+		Where("subject = ?", subject).
+		Where("grade_level = ?", gradeLevel).
+		Where("demographic_group = ?", demographicGroup).
+		LoadOne(r)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Purge permanently deletes school reports soft deleted before the given time
+func Purge(db *dbr.Tx, before time.Time) (int64, error) {
+	result, err := db.DeleteFrom("school_report").
+		Where("is_deleted = ?", true).
+		Where("deleted_at < ?", before).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeForSchools permanently deletes every school report of the given
+// schools, whether soft deleted or not
+func PurgeForSchools(db *dbr.Tx, schoolIds []int) (int64, error) {
+	if len(schoolIds) == 0 {
+		return 0, nil
+	}
+
+	result, err := db.DeleteFrom("school_report").
+		Where("school_id IN ?", schoolIds).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
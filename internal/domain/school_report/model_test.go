@@ -0,0 +1,161 @@
+package schoolreport
+
+import (
+	"academic-api/internal/dbtest"
+	rawdata "academic-api/internal/domain/raw_data"
+	"academic-api/internal/domain/school"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type NormalizeTestCase struct {
+	name     string
+	report   *SchoolReport
+	expected *SchoolReport
+}
+
+func TestSchoolReport_Normalize(t *testing.T) {
+	testCases := []NormalizeTestCase{
+		{
+			name:     "Normalized",
+			report:   &SchoolReport{Subject: "math", GradeLevel: "3-8", DemographicGroup: "all"},
+			expected: &SchoolReport{Subject: "math", GradeLevel: "3-8", DemographicGroup: "all"},
+		},
+		{
+			name:     "Upper case",
+			report:   &SchoolReport{Subject: "ELA", GradeLevel: "3-8", DemographicGroup: "Economically_Disadvantaged"},
+			expected: &SchoolReport{Subject: "ela", GradeLevel: "3-8", DemographicGroup: "economically_disadvantaged"},
+		},
+		{
+			name:     "Surrounding space",
+			report:   &SchoolReport{Subject: " Math\t", GradeLevel: " 4 ", DemographicGroup: "black\n"},
+			expected: &SchoolReport{Subject: "math", GradeLevel: "4", DemographicGroup: "black"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.report.normalize()
+			assert.DeepEqual(t, tc.report, tc.expected)
+		})
+	}
+}
+
+type PctProficientTestCase struct {
+	name        string
+	nTested     int
+	nProficient int
+	expectedPct float64
+}
+
+func TestSchoolReport_PctProficient(t *testing.T) {
+	testCases := []PctProficientTestCase{
+		{name: "Fraction", nTested: 40, nProficient: 13, expectedPct: 32.5},
+		{name: "All proficient", nTested: 25, nProficient: 25, expectedPct: 100},
+		{name: "None proficient", nTested: 25, nProficient: 0, expectedPct: 0},
+		{name: "None tested", nTested: 0, nProficient: 0, expectedPct: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, PctProficient(tc.nTested, tc.nProficient), tc.expectedPct)
+		})
+	}
+}
+
+type UpsertTestCase struct {
+	name           string
+	report         *SchoolReport
+	expectedStatus string
+	expectedId     int
+	expectedDataId int
+	expectedPct    float64
+}
+
+func TestSchoolReport_Upsert(t *testing.T) {
+	tx, err := dbtest.NewSession(t).Begin()
+	assert.NilError(t, err)
+	defer tx.RollbackUnlessCommitted()
+
+	assert.NilError(t, school.NewSchool("Lincoln Elementary", "AR", "").Create(tx))
+	for range 3 {
+		assert.NilError(t, rawdata.NewRawData(rawdata.ScopeSchool, "test", rawdata.StructureJson, "{}").Create(tx))
+	}
+
+	// Cases run in order against the same database
+	testCases := []UpsertTestCase{
+		{
+			name:           "New report",
+			report:         NewSchoolReport(1, 1, 2024, "math", "3", "all", 40, 10),
+			expectedStatus: UpsertInserted,
+			expectedId:     1,
+			expectedDataId: 1,
+			expectedPct:    25,
+		},
+		{
+			name:           "Identical re-post",
+			report:         NewSchoolReport(1, 1, 2024, "math", "3", "all", 40, 10),
+			expectedStatus: UpsertUnchanged,
+			expectedId:     1,
+			expectedDataId: 1,
+			expectedPct:    25,
+		},
+		{
+			name:           "Changed counts",
+			report:         NewSchoolReport(1, 2, 2024, "math", "3", "all", 40, 13),
+			expectedStatus: UpsertUpdated,
+			expectedId:     1,
+			expectedDataId: 2,
+			expectedPct:    32.5,
+		},
+		{
+			name:           "Same counts from newer raw data",
+			report:         NewSchoolReport(1, 3, 2024, "math", "3", "all", 40, 13),
+			expectedStatus: UpsertUpdated,
+			expectedId:     1,
+			expectedDataId: 3,
+			expectedPct:    32.5,
+		},
+		{
+			name:           "Key in another case",
+			report:         NewSchoolReport(1, 3, 2024, " MATH", "3", "All", 40, 13),
+			expectedStatus: UpsertUnchanged,
+			expectedId:     1,
+			expectedDataId: 3,
+			expectedPct:    32.5,
+		},
+		{
+			name:           "Another grade",
+			report:         NewSchoolReport(1, 3, 2024, "math", "4", "all", 20, 5),
+			expectedStatus: UpsertInserted,
+			expectedId:     2,
+			expectedDataId: 3,
+			expectedPct:    25,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, err := tc.report.Upsert(tx)
+			assert.NilError(t, err)
+			assert.Equal(t, status, tc.expectedStatus)
+			assert.Equal(t, tc.report.Id, tc.expectedId)
+			assert.Equal(t, tc.report.DataId, tc.expectedDataId)
+
+			stored, err := Load(tx, tc.expectedId)
+			assert.NilError(t, err)
+			assert.Equal(t, stored.DataId, tc.expectedDataId)
+			assert.Equal(t, stored.PctProficient, tc.expectedPct)
+
+			// The lineage of the report points at the raw data of the last write
+			lineage, err := (&SchoolReportRequest{}).Lineage(tx, tc.expectedId)
+			assert.NilError(t, err)
+			assert.Equal(t, lineage.RawData.Id, tc.expectedDataId)
+		})
+	}
+
+	var count int
+	assert.NilError(t, tx.Select("COUNT(*)").From("school_report").LoadOne(&count))
+	assert.Equal(t, count, 2)
+}
@@ -2,17 +2,37 @@ package schoolreport
 
 import (
 	"academic-api/internal/domain"
+	"academic-api/internal/domain/school"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gocraft/dbr/v2"
 )
 
+// Table is the school_report table, the columns its queries may select, sort
+// and facet by and the relations they may embed
+var Table = domain.Table{
+	Name:       "school_report",
+	Columns:    []string{"id", "school_id", "data_id", "academic_year", "subject", "grade_level", "demographic_group", "n_tested", "n_proficient", "pct_proficient", "is_deleted", "created_at", "updated_at", "deleted_at"},
+	Sortable:   []string{"id", "school_id", "academic_year", "subject", "grade_level", "demographic_group", "n_tested", "n_proficient", "pct_proficient"},
+	Facetable:  []string{"school_id", "data_id", "academic_year", "subject", "grade_level", "demographic_group"},
+	Includable: []string{"school"},
+}
+
 type SchoolReportRequest struct {
 	domain.Request
-	SchoolId         *int    `json:"school_id"`
-	AcademicYear     *int    `json:"academic_year"`
-	Subject          *string `json:"subject"`
-	GradeLevel       *string `json:"grade_level"`
-	DemographicGroup *string `json:"demographic_group"`
+	SchoolId         *domain.Filter[int]     `json:"school_id"`
+	DataId           *domain.Filter[int]     `json:"data_id"`
+	AcademicYear     *domain.Filter[int]     `json:"academic_year"`
+	Subject          *domain.Filter[string]  `json:"subject"`
+	GradeLevel       *domain.Filter[string]  `json:"grade_level"`
+	DemographicGroup *domain.Filter[string]  `json:"demographic_group"`
+	NTested          *domain.Filter[int]     `json:"n_tested"`
+	PctProficient    *domain.Filter[float64] `json:"pct_proficient"`
+	// Report left out of the results, set by lineage queries
+	exceptId *int
 }
 
 type SchoolReportResponse struct {
@@ -20,50 +40,138 @@ type SchoolReportResponse struct {
 	Data []*SchoolReport
 }
 
-func (r *SchoolReportRequest) ValidateFilter() error {
-	// TODO: implement filtering validation based on constant lists like in ValidateCreate
+func (r SchoolReportResponse) MarshalJSON() ([]byte, error) {
+	type response SchoolReportResponse
+	return domain.SparseJSON(response(r), &r.ApiResponse)
+}
+
+func (r *SchoolReportRequest) ParseQuery(values url.Values) error {
+	err := r.Request.ParseQuery(values)
+	if err != nil {
+		return err
+	}
+
+	r.SchoolId, err = domain.QueryIntFilter(values, "school_id")
+	if err != nil {
+		return err
+	}
+
+	r.DataId, err = domain.QueryIntFilter(values, "data_id")
+	if err != nil {
+		return err
+	}
+
+	r.AcademicYear, err = domain.QueryIntFilter(values, "academic_year")
+	if err != nil {
+		return err
+	}
+
+	r.Subject, err = domain.QueryStringFilter(values, "subject")
+	if err != nil {
+		return err
+	}
+
+	r.GradeLevel, err = domain.QueryStringFilter(values, "grade_level")
+	if err != nil {
+		return err
+	}
+
+	r.DemographicGroup, err = domain.QueryStringFilter(values, "demographic_group")
+	if err != nil {
+		return err
+	}
+
+	r.NTested, err = domain.QueryIntFilter(values, "n_tested")
+	if err != nil {
+		return err
+	}
+
+	r.PctProficient, err = domain.QueryFloatFilter(values, "pct_proficient")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (r *SchoolReportRequest) ApplyFilters(query *dbr.SelectStmt) *dbr.SelectStmt {
-	if r.Id != nil {
-		query = query.Where("id = ?", *r.Id)
+func (r *SchoolReportRequest) ValidateFilter() error {
+	r.normalizeFilter()
+
+	v := &domain.Validator{}
+	r.Request.Validate(v, Table)
+	if r.Includes("school") {
+		r.Require("school_id")
 	}
 
-	if r.SchoolId != nil {
-		query = query.Where("school_id = ?", *r.SchoolId)
+	r.SchoolId.Validate(v, "school_id")
+	r.DataId.Validate(v, "data_id")
+	r.AcademicYear.Validate(v, "academic_year")
+	r.Subject.Validate(v, "subject")
+	r.GradeLevel.Validate(v, "grade_level")
+	r.DemographicGroup.Validate(v, "demographic_group")
+	r.NTested.Validate(v, "n_tested")
+	r.PctProficient.Validate(v, "pct_proficient")
+
+	for _, year := range r.AcademicYear.Values() {
+		v.Range("academic_year", year, minAcademicYear, time.Now().Year()+1)
 	}
 
-	if r.AcademicYear != nil {
-		query = query.Where("academic_year = ?", *r.AcademicYear)
+	for _, subject := range r.Subject.Values() {
+		v.OneOf("subject", subject, validSubjects)
 	}
 
-	if r.DemographicGroup != nil {
-		query = query.Where("demographic_group = ?", *r.DemographicGroup)
+	for _, gradeLevel := range r.GradeLevel.Values() {
+		v.OneOf("grade_level", gradeLevel, validGradeLevels)
 	}
 
-	if r.GradeLevel != nil {
-		query = query.Where("grade_level = ?", *r.GradeLevel)
+	for _, group := range r.DemographicGroup.Values() {
+		v.OneOf("demographic_group", group, validDemographicGroups)
+	}
+
+	return v.Err()
+}
+
+// normalizeFilter trims and lowercases the vocabulary filters so they match
+// the values stored in the schema
+func (r *SchoolReportRequest) normalizeFilter() {
+	normalize := func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
 	}
 
-	if r.Subject != nil {
-		query.Where("subject = ?", *r.Subject)
+	r.Subject.Map(normalize)
+	r.GradeLevel.Map(normalize)
+	r.DemographicGroup.Map(normalize)
+}
+
+func (r *SchoolReportRequest) ApplyFilters(query *dbr.SelectStmt) *dbr.SelectStmt {
+	if r.Id != nil {
+		query = query.Where("id = ?", *r.Id)
+	}
+	if r.exceptId != nil {
+		query = query.Where("id <> ?", *r.exceptId)
 	}
 
+	query = r.SchoolId.Apply(query, "school_id")
+	query = r.DataId.Apply(query, "data_id")
+	query = r.AcademicYear.Apply(query, "academic_year")
+	query = r.Subject.Apply(query, "subject")
+	query = r.GradeLevel.Apply(query, "grade_level")
+	query = r.DemographicGroup.Apply(query, "demographic_group")
+	query = r.NTested.Apply(query, "n_tested")
+	query = r.PctProficient.Apply(query, "pct_proficient")
+
 	return query
 }
 
-func (r *SchoolReportRequest) ApplyCursors(query *dbr.SelectStmt, response *SchoolReportResponse) (*dbr.SelectStmt, *SchoolReportResponse) {
-	return domain.ApplyCursors(&r.Request, query, response, func(resp *SchoolReportResponse) *domain.ApiResponse {
-		return &resp.ApiResponse
-	})
+func (r *SchoolReportRequest) ApplyCursors(query *dbr.SelectStmt) *dbr.SelectStmt {
+	return domain.ApplyCursors(&r.Request, Table, query)
 }
 
 func (r *SchoolReportRequest) Query(db *dbr.Tx) (*SchoolReportResponse, error) {
-	return domain.Query(
+	resp, err := domain.Query(
 		r,
 		db,
-		"school",
+		Table,
 		func() *SchoolReportResponse { return &SchoolReportResponse{} },
 		func(req *SchoolReportRequest) *domain.Request { return &req.Request },
 		func(resp *SchoolReportResponse) *domain.ApiResponse { return &resp.ApiResponse },
@@ -71,4 +179,36 @@ func (r *SchoolReportRequest) Query(db *dbr.Tx) (*SchoolReportResponse, error) {
 		r.ValidateFilter,
 		r.ApplyFilters,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Includes("school") {
+		err = includeSchools(db, resp.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// includeSchools embeds the parent school in each report, loading every
+// school of the page in one query
+func includeSchools(db *dbr.Tx, reports []*SchoolReport) error {
+	ids := make([]int, 0, len(reports))
+	for _, report := range reports {
+		ids = append(ids, report.SchoolId)
+	}
+	slices.Sort(ids)
+
+	schools, err := school.LoadMany(db, slices.Compact(ids))
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		report.School = schools[report.SchoolId]
+	}
+	return nil
 }
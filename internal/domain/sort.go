@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// Sort directions
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+var validSortDirs = []string{SortAsc, SortDesc}
+
+// SortField orders query results by a column, ascending unless Dir is desc
+type SortField struct {
+	Field string `json:"field"`
+	Dir   string `json:"dir"`
+}
+
+// Desc reports whether the field sorts in descending order
+func (s SortField) Desc() bool {
+	return strings.EqualFold(s.Dir, SortDesc)
+}
+
+// Sort is an ordered list of sort fields. Rows that tie on every field are
+// ordered by id so pages are stable.
+type Sort []SortField
+
+// QuerySort parses a comma separated list of fields, each either prefixed
+// with - for descending order or suffixed with :asc or :desc
+func QuerySort(values url.Values, key string) Sort {
+	if !values.Has(key) {
+		return nil
+	}
+
+	var sort Sort
+	for part := range strings.SplitSeq(values.Get(key), ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: part}
+		if name, ok := strings.CutPrefix(part, "-"); ok {
+			field = SortField{Field: name, Dir: SortDesc}
+		} else if name, dir, ok := strings.Cut(part, ":"); ok {
+			field = SortField{Field: name, Dir: dir}
+		}
+		sort = append(sort, field)
+	}
+	return sort
+}
+
+// Validate checks that every field is one of the sortable columns, appears
+// once and has a known direction
+func (s Sort) Validate(v *Validator, sortable []string) {
+	seen := map[string]bool{}
+	for _, field := range s {
+		v.OneOf("sort", field.Field, sortable)
+		if field.Dir != "" {
+			v.OneOf("sort", field.Dir, validSortDirs)
+		}
+		v.Check(!seen[field.Field], "sort", CodeInvalidChoice, fmt.Sprintf("Field %q is sorted more than once.", field.Field))
+		seen[field.Field] = true
+	}
+}
+
+// keys returns the sort fields followed by id, unless the request already
+// sorts by id, so every row has a unique position
+func (s Sort) keys() Sort {
+	if slices.ContainsFunc(s, func(field SortField) bool { return field.Field == "id" }) {
+		return s
+	}
+	return append(slices.Clone(s), SortField{Field: "id", Dir: SortAsc})
+}
+
+// Apply orders the query by the sort keys, reversed when walking backwards.
+// Expr returns the SQL expression of a sort key. NULL sorts before every
+// value, as SQLite orders it, which After relies on to page past NULLs.
+func (s Sort) Apply(query *dbr.SelectStmt, reverse bool, expr func(field string) string) *dbr.SelectStmt {
+	for _, key := range s.keys() {
+		query = query.OrderDir(expr(key.Field), key.Desc() == reverse)
+	}
+	return query
+}
+
+// After matches the rows that come after a row with the given sort key values
+// in sort order, or before it when reverse is set. Expr returns the SQL
+// expression of a sort key. A nil value stands for NULL, which sorts before
+// every other value.
+func (s Sort) After(values []any, reverse bool, expr func(field string) string) dbr.Builder {
+	keys := s.keys()
+	conds := make([]dbr.Builder, 0, len(keys))
+	for i, key := range keys {
+		next, ok := beyond(expr(key.Field), values[i], key.Desc() == reverse)
+		if !ok {
+			continue
+		}
+
+		and := make([]dbr.Builder, 0, i+1)
+		for j, prev := range keys[:i] {
+			and = append(and, equal(expr(prev.Field), values[j]))
+		}
+		and = append(and, next)
+		conds = append(conds, dbr.And(and...))
+	}
+	return dbr.Or(conds...)
+}
+
+// equal matches the rows whose column equals value, NULL matching NULL
+func equal(column string, value any) dbr.Builder {
+	if value == nil {
+		return dbr.Expr(column + " IS NULL")
+	}
+	return dbr.Expr(column+" = ?", value)
+}
+
+// beyond matches the rows whose column is greater than value, or less than it
+// unless ascending is set. It returns false when no value is beyond, as none
+// is less than NULL.
+func beyond(column string, value any, ascending bool) (dbr.Builder, bool) {
+	switch {
+	case value == nil && ascending:
+		return dbr.Expr(column + " IS NOT NULL"), true
+	case value == nil:
+		return nil, false
+	case ascending:
+		return dbr.Expr(column+" > ?", value), true
+	default:
+		return dbr.Or(dbr.Expr(column+" < ?", value), dbr.Expr(column+" IS NULL")), true
+	}
+}
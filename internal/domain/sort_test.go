@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"net/url"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type QuerySortTestCase struct {
+	name           string
+	query          string
+	expectedResult Sort
+}
+
+func TestSort_QuerySort(t *testing.T) {
+	testCases := []QuerySortTestCase{
+		{
+			name:           "Absent",
+			query:          "",
+			expectedResult: nil,
+		},
+		{
+			name:           "Prefix and suffix directions",
+			query:          "sort=-pct_proficient,academic_year:asc,subject",
+			expectedResult: Sort{{Field: "pct_proficient", Dir: SortDesc}, {Field: "academic_year", Dir: SortAsc}, {Field: "subject"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, QuerySort(values, "sort"), tc.expectedResult)
+		})
+	}
+}
+
+type SortValidateTestCase struct {
+	name          string
+	sort          Sort
+	expectedCodes []string
+}
+
+func TestSort_Validate(t *testing.T) {
+	sortable := []string{"id", "pct_proficient"}
+	testCases := []SortValidateTestCase{
+		{
+			name: "Sortable",
+			sort: Sort{{Field: "pct_proficient", Dir: SortDesc}, {Field: "id"}},
+		},
+		{
+			name:          "Unknown field",
+			sort:          Sort{{Field: "school_name"}},
+			expectedCodes: []string{CodeInvalidChoice},
+		},
+		{
+			name:          "Unknown direction",
+			sort:          Sort{{Field: "id", Dir: "up"}},
+			expectedCodes: []string{CodeInvalidChoice},
+		},
+		{
+			name:          "Repeated field",
+			sort:          Sort{{Field: "id"}, {Field: "id", Dir: SortDesc}},
+			expectedCodes: []string{CodeInvalidChoice},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &Validator{}
+			tc.sort.Validate(v, sortable)
+			var codes []string
+			for _, fieldErr := range v.errs {
+				codes = append(codes, fieldErr.Code)
+			}
+			assert.DeepEqual(t, codes, tc.expectedCodes)
+		})
+	}
+}
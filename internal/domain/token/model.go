@@ -0,0 +1,108 @@
+package token
+
+import (
+	"academic-api/internal/domain"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrAlreadyRevoked is returned when a token is used up after it was revoked
+var ErrAlreadyRevoked = errors.New("Token has already been revoked.")
+
+// RevokedToken is a JWT id that must no longer be accepted
+type RevokedToken struct {
+	Jti       string          `json:"jti"`
+	Subject   string          `json:"subject"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	RevokedAt domain.NullTime `json:"revoked_at"`
+}
+
+func NewRevokedToken(jti string, subject string, expiresAt time.Time) *RevokedToken {
+	return &RevokedToken{
+		Jti:       jti,
+		Subject:   subject,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (t *RevokedToken) ValidateCreate() error {
+	if t.Jti == "" {
+		return fmt.Errorf("Token without jti cannot be revoked.")
+	}
+	return nil
+}
+
+func (t *RevokedToken) Create(db *dbr.Tx) error {
+	err := t.ValidateCreate()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to validate revoked token for create.")
+		return err
+	}
+
+	// Revoking a token twice is not an error
+	_, err = db.InsertBySql(
+		"INSERT OR IGNORE INTO revoked_token (jti, subject, expires_at, revoked_at) VALUES (?, ?, ?, ?)",
+		t.Jti, t.Subject, t.ExpiresAt, time.Now(),
+	).Exec()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert revoked token to database.")
+		return err
+	}
+
+	return nil
+}
+
+// Consume revokes a token that may only be used once. Unlike Create, it
+// fails with ErrAlreadyRevoked when the jti is already on the list, so only
+// one of two concurrent uses of the token succeeds.
+func (t *RevokedToken) Consume(db *dbr.Tx) error {
+	err := t.ValidateCreate()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to validate revoked token for create.")
+		return err
+	}
+
+	result, err := db.InsertBySql(
+		"INSERT OR IGNORE INTO revoked_token (jti, subject, expires_at, revoked_at) VALUES (?, ?, ?, ?)",
+		t.Jti, t.Subject, t.ExpiresAt, time.Now(),
+	).Exec()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to insert revoked token to database.")
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrAlreadyRevoked
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the token with the given jti has been revoked
+func IsRevoked(db dbr.SessionRunner, jti string) (bool, error) {
+	var count int
+	err := db.Select("COUNT(*)").
+		From("revoked_token").
+		Where("jti = ?", jti).
+		LoadOne(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired removes revoked tokens that have expired on their own
+func DeleteExpired(db *dbr.Tx, now time.Time) error {
+	_, err := db.DeleteFrom("revoked_token").
+		Where("expires_at < ?", now).
+		Exec()
+	return err
+}
@@ -0,0 +1,32 @@
+package token
+
+const (
+	GrantTypeApiKey            = "api_key"
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeRefreshToken      = "refresh_token"
+
+	TokenTypeBearer = "Bearer"
+)
+
+// TokenRequest exchanges credentials for a token pair. Client credentials
+// are the name and key of an API_KEYS entry.
+type TokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	ApiKey       string `json:"api_key"`
+	ClientId     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RevokeRequest names an access or refresh token to revoke
+type RevokeRequest struct {
+	Token string `json:"token"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	// Access token lifetime in seconds
+	ExpiresIn int64 `json:"expires_in"`
+}
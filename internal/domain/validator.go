@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"academic-api/internal/common"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Field error codes
+const (
+	CodeRequired         = "required"
+	CodeInvalidLength    = "invalid_length"
+	CodeInvalidChoice    = "invalid_choice"
+	CodeOutOfRange       = "out_of_range"
+	CodeInvalidOperator  = "invalid_operator"
+	CodeInvalidReference = "invalid_reference"
+	CodeInvalidFormat    = "invalid_format"
+)
+
+// Validator collects every field violation of a model or request filter so
+// they can be reported at once
+type Validator struct {
+	errs common.FieldErrors
+}
+
+// Add records a violation of field
+func (v *Validator) Add(field string, code string, message string) {
+	v.errs = append(v.errs, common.FieldError{Field: field, Code: code, Message: message})
+}
+
+// Check records a violation of field unless ok
+func (v *Validator) Check(ok bool, field string, code string, message string) {
+	if !ok {
+		v.Add(field, code, message)
+	}
+}
+
+// Required checks that value is not empty
+func (v *Validator) Required(field string, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, CodeRequired, "Value is required.")
+}
+
+// Length checks that value has exactly length characters
+func (v *Validator) Length(field string, value string, length int) {
+	v.Check(len(value) == length, field, CodeInvalidLength, fmt.Sprintf("Value must be %d characters long.", length))
+}
+
+// OneOf checks that value, ignoring case, is one of choices
+func (v *Validator) OneOf(field string, value string, choices []string) {
+	v.Check(
+		slices.Contains(choices, strings.ToLower(value)),
+		field,
+		CodeInvalidChoice,
+		fmt.Sprintf("Value %q must be one of %s.", value, strings.Join(choices, ", ")),
+	)
+}
+
+// Min checks that value is at least min
+func (v *Validator) Min(field string, value int, min int) {
+	v.Check(value >= min, field, CodeOutOfRange, fmt.Sprintf("Value must be at least %d.", min))
+}
+
+// Range checks that value is between min and max inclusive
+func (v *Validator) Range(field string, value int, min int, max int) {
+	v.Check(value >= min && value <= max, field, CodeOutOfRange, fmt.Sprintf("Value must be between %d and %d.", min, max))
+}
+
+// Valid reports whether no violation has been recorded
+func (v *Validator) Valid() bool {
+	return len(v.errs) == 0
+}
+
+// Err returns the recorded violations as a validation error, or nil
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return common.ErrValidation.Wrap(v.errs)
+}
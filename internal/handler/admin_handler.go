@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"academic-api/internal/common"
+	"academic-api/internal/domain"
+	"academic-api/internal/service"
+	"fmt"
+	"net/http"
+)
+
+type IAdminHandler interface {
+	Purge(w http.ResponseWriter, r *http.Request)
+}
+
+type AdminHandler struct {
+	IAdminHandler
+	purgeService service.IPurgeService
+}
+
+func NewAdminHandler(purgeService service.IPurgeService) *AdminHandler {
+	return &AdminHandler{purgeService: purgeService}
+}
+
+// Purge permanently deletes records soft deleted more than older_than_days ago
+func (h *AdminHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	days, err := domain.QueryInt(r.URL.Query(), "older_than_days")
+	if err != nil {
+		common.WriteApiErrorResponse(w, err)
+		return
+	}
+	if days == nil || *days < 0 {
+		common.WriteApiErrorResponse(w, common.ErrBadRequest.Wrap(fmt.Errorf("Query parameter older_than_days must be a non-negative number of days.")))
+		return
+	}
+
+	result, err := h.purgeService.Purge(*days)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to purge deleted records: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Deleted records purged.",
+		Data:    result,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"academic-api/internal/common"
+	"academic-api/internal/service"
+	"fmt"
+	"net/http"
+)
+
+type IAuthHandler interface {
+	Token(w http.ResponseWriter, r *http.Request)
+	Refresh(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+}
+
+type AuthHandler struct {
+	IAuthHandler
+	service service.IAuthService
+}
+
+func NewAuthHandler(service service.IAuthService) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+func (h *AuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for token request present."))
+		return
+	}
+
+	tokens, err := h.service.IssueToken(r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to issue token: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Token issued.",
+		Data:    tokens,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for refresh request present."))
+		return
+	}
+
+	tokens, err := h.service.Refresh(r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to refresh token: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Token refreshed.",
+		Data:    tokens,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *AuthHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for revoke request present."))
+		return
+	}
+
+	err := h.service.Revoke(r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to revoke token: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Token revoked.",
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
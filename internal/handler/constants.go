@@ -1,6 +1,14 @@
 package handler
 
 const (
+	adminPath             = "/admin"
+	adminPathName         = "admin"
+	authPath              = "/auth"
+	authPathName          = "auth"
+	districtsPath         = "/districts"
+	districtsPathName     = "districts"
+	rawDataPath           = "/raw-data"
+	rawDataPathName       = "rawData"
 	schoolsPath           = "/schools"
 	schoolsPathName       = "schools"
 	schoolReportsPath     = "/school-reports"
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"academic-api/internal/common"
+	"academic-api/internal/service"
+	"fmt"
+	"net/http"
+)
+
+type IDistrictHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Query(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Restore(w http.ResponseWriter, r *http.Request)
+}
+
+type DistrictHandler struct {
+	IDistrictHandler
+	service service.IDistrictService
+}
+
+func NewDistrictHandler(service service.IDistrictService) *DistrictHandler {
+	return &DistrictHandler{service: service}
+}
+
+func (h *DistrictHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for creating district object present."))
+		return
+	}
+
+	districtObj, err := h.service.Create(r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to create new district object: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "District object created.",
+		Data:    districtObj,
+	}
+
+	common.WriteCreatedResponse(w, respBody)
+}
+
+func (h *DistrictHandler) Query(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for querying district object present."))
+		return
+	}
+
+	districts, err := h.service.Query(r.Context(), r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query district objects: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "District object found.",
+		Data:    districts,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+// List queries district objects using URL query parameters as filters
+func (h *DistrictHandler) List(w http.ResponseWriter, r *http.Request) {
+	districts, err := h.service.QueryParams(r.Context(), r.URL.Query())
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query district objects: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "District objects found.",
+		Data:    districts,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *DistrictHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Get(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to get district object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "District object found.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+// Update replaces the district object on PUT and merges the body into it on PATCH
+func (h *DistrictHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for updating district object present."))
+		return
+	}
+
+	obj, err := h.service.Update(id, r.Body, r.Method == http.MethodPatch)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to update district object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "District object updated.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *DistrictHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Delete(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to delete district object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "District object deleted.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *DistrictHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Restore(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to restore district object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "District object restored.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	idPathVar = "id"
+	idPath    = "/{" + idPathVar + ":[0-9]+}"
+)
+
+// pathId returns the record id from the request path
+func pathId(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)[idPathVar])
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("Invalid id in path: %s", mux.Vars(r)[idPathVar])
+	}
+	return id, nil
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"academic-api/internal/common"
+	"academic-api/internal/service"
+	"fmt"
+	"net/http"
+)
+
+type IRawDataHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Query(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+}
+
+type RawDataHandler struct {
+	IRawDataHandler
+	service service.IRawDataService
+}
+
+func NewRawDataHandler(service service.IRawDataService) *RawDataHandler {
+	return &RawDataHandler{service: service}
+}
+
+func (h *RawDataHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for creating raw data object present."))
+		return
+	}
+
+	rawDataObj, err := h.service.Create(r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to create new raw data object: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Raw data object created.",
+		Data:    rawDataObj,
+	}
+
+	common.WriteCreatedResponse(w, respBody)
+}
+
+func (h *RawDataHandler) Query(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for querying raw data object present."))
+		return
+	}
+
+	rawData, err := h.service.Query(r.Context(), r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query raw data objects: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Raw data object found.",
+		Data:    rawData,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+// List queries raw data objects using URL query parameters as filters
+func (h *RawDataHandler) List(w http.ResponseWriter, r *http.Request) {
+	rawData, err := h.service.QueryParams(r.Context(), r.URL.Query())
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query raw data objects: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Raw data objects found.",
+		Data:    rawData,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *RawDataHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Get(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to get raw data object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "Raw data object found.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
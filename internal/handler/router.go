@@ -3,6 +3,7 @@ package handler
 import (
 	"academic-api/internal/middleware"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -12,47 +13,244 @@ type IRouter interface {
 }
 
 type Router struct {
+	districtHandler     IDistrictHandler
+	rawDataHandler      IRawDataHandler
 	schoolHandler       ISchoolHandler
 	schoolReportHandler ISchoolReportHandler
+	authHandler         IAuthHandler
+	adminHandler        IAdminHandler
 	auth                middleware.IAuthMiddleware
+	apiVersion          string
 }
 
-func NewRouter(schoolHandler ISchoolHandler, schoolReportHandler ISchoolReportHandler, auth middleware.IAuthMiddleware) *Router {
+// NewRouter serves the resource routes under the apiVersion prefix (for
+// example "v1" or "/api/v1") next to the legacy /put and /get routes.
+func NewRouter(districtHandler IDistrictHandler, rawDataHandler IRawDataHandler, schoolHandler ISchoolHandler, schoolReportHandler ISchoolReportHandler, authHandler IAuthHandler, adminHandler IAdminHandler, auth middleware.IAuthMiddleware, apiVersion string) *Router {
 	return &Router{
+		districtHandler:     districtHandler,
+		rawDataHandler:      rawDataHandler,
 		schoolHandler:       schoolHandler,
 		schoolReportHandler: schoolReportHandler,
+		authHandler:         authHandler,
+		adminHandler:        adminHandler,
 		auth:                auth,
+		apiVersion:          apiVersion,
 	}
 }
 
 func (r *Router) GetRouteHandler() (http.Handler, error) {
-	router := mux.NewRouter().StrictSlash(true)
+	root := mux.NewRouter().StrictSlash(true)
 
+	// Token routes authenticate with the credentials in their body
+	root.
+		Path(authPath + "/token").
+		Name(authPathName + "Token").
+		Methods(http.MethodPost).
+		HandlerFunc(r.authHandler.Token)
+
+	root.
+		Path(authPath + "/refresh").
+		Name(authPathName + "Refresh").
+		Methods(http.MethodPost).
+		HandlerFunc(r.authHandler.Refresh)
+
+	root.
+		Path(authPath + "/revoke").
+		Name(authPathName + "Revoke").
+		Methods(http.MethodPost).
+		HandlerFunc(r.authHandler.Revoke)
+
+	// Every other route requires an authenticated principal
+	router := root.NewRoute().Subrouter()
+	router.Use(r.auth.GetMiddleware())
+
+	// Legacy routes
 	router.
 		Path(schoolsPath + "/put").
 		Name(schoolsPathName + "Put").
 		Methods(http.MethodPost).
-		HandlerFunc(r.schoolHandler.Create)
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolHandler.Create))
 
 	router.
 		Path(schoolsPath + "/get").
 		Name(schoolsPathName + "Get").
 		Methods(http.MethodPost).
-		HandlerFunc(r.schoolHandler.Query)
+		Handler(requireScope(middleware.ScopeReportsRead, r.schoolHandler.Query))
 
 	router.
 		Path(schoolReportsPath + "/put").
 		Name(schoolReportsPathName + "Put").
 		Methods(http.MethodPost).
-		HandlerFunc(r.schoolReportHandler.Create)
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolReportHandler.Create))
 
 	router.
 		Path(schoolReportsPath + "/get").
 		Name(schoolReportsPathName + "Get").
 		Methods(http.MethodPost).
-		HandlerFunc(r.schoolReportHandler.Query)
+		Handler(requireScope(middleware.ScopeReportsRead, r.schoolReportHandler.Query))
 
-	router.Use(r.auth.GetMiddleware())
+	router.
+		Path(districtsPath + "/get").
+		Name(districtsPathName + "Get").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeReportsRead, r.districtHandler.Query))
+
+	router.
+		Path(rawDataPath + "/get").
+		Name(rawDataPathName + "Get").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeReportsRead, r.rawDataHandler.Query))
+
+	versioned := router.PathPrefix("/" + strings.Trim(r.apiVersion, "/")).Subrouter()
+
+	versioned.
+		Path(districtsPath).
+		Name(districtsPathName + "List").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.districtHandler.List))
+
+	versioned.
+		Path(districtsPath).
+		Name(districtsPathName + "Create").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.districtHandler.Create))
+
+	versioned.
+		Path(districtsPath + idPath).
+		Name(districtsPathName + "Read").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.districtHandler.Get))
+
+	versioned.
+		Path(districtsPath+idPath).
+		Name(districtsPathName+"Update").
+		Methods(http.MethodPut, http.MethodPatch).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.districtHandler.Update))
+
+	versioned.
+		Path(districtsPath + idPath).
+		Name(districtsPathName + "Delete").
+		Methods(http.MethodDelete).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.districtHandler.Delete))
+
+	versioned.
+		Path(districtsPath + idPath + "/restore").
+		Name(districtsPathName + "Restore").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeAdmin, r.districtHandler.Restore))
+
+	versioned.
+		Path(rawDataPath).
+		Name(rawDataPathName + "List").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.rawDataHandler.List))
+
+	versioned.
+		Path(rawDataPath).
+		Name(rawDataPathName + "Create").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.rawDataHandler.Create))
+
+	versioned.
+		Path(rawDataPath + idPath).
+		Name(rawDataPathName + "Read").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.rawDataHandler.Get))
+
+	versioned.
+		Path(schoolsPath).
+		Name(schoolsPathName + "List").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.schoolHandler.List))
+
+	versioned.
+		Path(schoolsPath).
+		Name(schoolsPathName + "Create").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolHandler.Create))
+
+	versioned.
+		Path(schoolsPath + idPath).
+		Name(schoolsPathName + "Read").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.schoolHandler.Get))
+
+	versioned.
+		Path(schoolsPath+idPath).
+		Name(schoolsPathName+"Update").
+		Methods(http.MethodPut, http.MethodPatch).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolHandler.Update))
+
+	versioned.
+		Path(schoolsPath + idPath).
+		Name(schoolsPathName + "Delete").
+		Methods(http.MethodDelete).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolHandler.Delete))
+
+	versioned.
+		Path(schoolsPath + idPath + "/restore").
+		Name(schoolsPathName + "Restore").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeAdmin, r.schoolHandler.Restore))
+
+	versioned.
+		Path(schoolReportsPath).
+		Name(schoolReportsPathName + "List").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.schoolReportHandler.List))
+
+	versioned.
+		Path(schoolReportsPath).
+		Name(schoolReportsPathName + "Create").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolReportHandler.Create))
+
+	versioned.
+		Path(schoolReportsPath + "/bulk").
+		Name(schoolReportsPathName + "BulkCreate").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolReportHandler.BulkCreate))
+
+	versioned.
+		Path(schoolReportsPath + idPath).
+		Name(schoolReportsPathName + "Read").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.schoolReportHandler.Get))
+
+	versioned.
+		Path(schoolReportsPath + idPath + "/lineage").
+		Name(schoolReportsPathName + "Lineage").
+		Methods(http.MethodGet).
+		Handler(requireScope(middleware.ScopeReportsRead, r.schoolReportHandler.Lineage))
+
+	versioned.
+		Path(schoolReportsPath+idPath).
+		Name(schoolReportsPathName+"Update").
+		Methods(http.MethodPut, http.MethodPatch).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolReportHandler.Update))
+
+	versioned.
+		Path(schoolReportsPath + idPath).
+		Name(schoolReportsPathName + "Delete").
+		Methods(http.MethodDelete).
+		Handler(requireScope(middleware.ScopeReportsWrite, r.schoolReportHandler.Delete))
+
+	versioned.
+		Path(schoolReportsPath + idPath + "/restore").
+		Name(schoolReportsPathName + "Restore").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeAdmin, r.schoolReportHandler.Restore))
+
+	versioned.
+		Path(adminPath + "/purge").
+		Name(adminPathName + "Purge").
+		Methods(http.MethodPost).
+		Handler(requireScope(middleware.ScopeAdmin, r.adminHandler.Purge))
+
+	return root, nil
+}
 
-	return router, nil
+// requireScope wraps handlerFunc so it is only reachable by principals granted scope
+func requireScope(scope string, handlerFunc http.HandlerFunc) http.Handler {
+	return middleware.NewScopeMiddleware(scope).GetMiddleware()(handlerFunc)
 }
@@ -10,6 +10,11 @@ import (
 type ISchoolHandler interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Query(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Restore(w http.ResponseWriter, r *http.Request)
 }
 
 type SchoolHandler struct {
@@ -29,7 +34,7 @@ func (h *SchoolHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	schoolObj, err := h.service.Create(r.Body)
 	if err != nil {
-		common.WriteInternalErrorResponse(w, fmt.Errorf("Failed to create new school object: %d", err))
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to create new school object: %w", err))
 		return
 	}
 
@@ -47,9 +52,9 @@ func (h *SchoolHandler) Query(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	schools, err := h.service.Query(r.Body)
+	schools, err := h.service.Query(r.Context(), r.Body)
 	if err != nil {
-		common.WriteNotFoundResponse(w, fmt.Errorf("Failed to find school object: %d", err))
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query school objects: %w", err))
 		return
 	}
 
@@ -60,3 +65,108 @@ func (h *SchoolHandler) Query(w http.ResponseWriter, r *http.Request) {
 
 	common.WriteOkResponse(w, respBody)
 }
+
+// List queries school objects using URL query parameters as filters
+func (h *SchoolHandler) List(w http.ResponseWriter, r *http.Request) {
+	schools, err := h.service.QueryParams(r.Context(), r.URL.Query())
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query school objects: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School objects found.",
+		Data:    schools,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *SchoolHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Get(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to get school object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School object found.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+// Update replaces the school object on PUT and merges the body into it on PATCH
+func (h *SchoolHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for updating school object present."))
+		return
+	}
+
+	obj, err := h.service.Update(id, r.Body, r.Method == http.MethodPatch)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to update school object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School object updated.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *SchoolHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Delete(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to delete school object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School object deleted.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *SchoolHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Restore(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to restore school object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School object restored.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
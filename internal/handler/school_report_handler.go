@@ -2,16 +2,26 @@ package handler
 
 import (
 	"academic-api/internal/common"
+	"academic-api/internal/domain"
+	schoolreport "academic-api/internal/domain/school_report"
 	"academic-api/internal/service"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/sirupsen/logrus"
 )
 
 type ISchoolReportHandler interface {
 	Create(w http.ResponseWriter, r *http.Request)
+	BulkCreate(w http.ResponseWriter, r *http.Request)
 	Query(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Lineage(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Restore(w http.ResponseWriter, r *http.Request)
 }
 
 type SchoolReportHandler struct {
@@ -30,9 +40,19 @@ func (h *SchoolReportHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	upsert, err := upsertMode(r.URL.Query())
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+	if upsert {
+		h.upsert(w, r)
+		return
+	}
+
 	schoolObj, err := h.service.Create(r.Body)
 	if err != nil {
-		common.WriteInternalErrorResponse(w, fmt.Errorf("Failed to create new school report object: %d", err))
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to create new school report object: %w", err))
 		return
 	}
 
@@ -44,15 +64,99 @@ func (h *SchoolReportHandler) Create(w http.ResponseWriter, r *http.Request) {
 	common.WriteCreatedResponse(w, respBody)
 }
 
+// upsert creates the school report of the request body or updates the counts
+// of the school report with the same natural key
+func (h *SchoolReportHandler) upsert(w http.ResponseWriter, r *http.Request) {
+	reportObj, status, err := h.service.Upsert(r.Body)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to upsert school report object: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: fmt.Sprintf("School report object %s.", status),
+		Data:    schoolreport.UpsertResponse{Status: status, SchoolReport: reportObj},
+	}
+
+	if status == schoolreport.UpsertInserted {
+		common.WriteCreatedResponse(w, respBody)
+		return
+	}
+	common.WriteOkResponse(w, respBody)
+}
+
+// BulkCreate creates the school reports of a JSON array or NDJSON body. The
+// atomic query parameter makes the request all or nothing and chunk_size sets
+// the rows committed per transaction of a best effort request. With
+// mode=upsert, rows that already exist are updated instead of failing.
+func (h *SchoolReportHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for creating school report objects present."))
+		return
+	}
+
+	params := r.URL.Query()
+	atomic, err := domain.QueryBool(params, "atomic")
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+	upsert, err := upsertMode(params)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+	chunkSize, err := domain.QueryInt(params, "chunk_size")
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+	if chunkSize == nil {
+		chunkSize = new(int)
+	} else if *chunkSize <= 0 {
+		common.WriteBadRequestResponse(w, fmt.Errorf("Query parameter chunk_size must be a positive number of rows."))
+		return
+	}
+
+	result, err := h.service.BulkCreate(r.Body, atomic, upsert, *chunkSize)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to create school report objects: %w", err))
+		return
+	}
+
+	switch {
+	case result.Created == result.Total:
+		common.WriteCreatedResponse(w, common.ResponseBody{
+			Message: "School report objects created.",
+			Data:    result,
+		})
+	case result.Failed == 0:
+		common.WriteOkResponse(w, common.ResponseBody{
+			Message: "School report objects upserted.",
+			Data:    result,
+		})
+	case result.Atomic:
+		common.WriteHttpResponse(w, common.ResponseBody{
+			Message: "School report objects rejected.",
+			Data:    result,
+		}, http.StatusUnprocessableEntity)
+	default:
+		common.WriteHttpResponse(w, common.ResponseBody{
+			Message: "Some school report objects failed.",
+			Data:    result,
+		}, http.StatusMultiStatus)
+	}
+}
+
 func (h *SchoolReportHandler) Query(w http.ResponseWriter, r *http.Request) {
 	if r.Body == nil {
 		common.WriteBadRequestResponse(w, fmt.Errorf("No body for querying school report object present."))
 		return
 	}
 
-	schools, err := h.service.Query(r.Body)
+	schools, err := h.service.Query(r.Context(), r.Body)
 	if err != nil {
-		common.WriteNotFoundResponse(w, fmt.Errorf("Failed to find school report object: %d", err))
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query school report objects: %w", err))
 		return
 	}
 
@@ -63,3 +167,144 @@ func (h *SchoolReportHandler) Query(w http.ResponseWriter, r *http.Request) {
 
 	common.WriteOkResponse(w, respBody)
 }
+
+// List queries school report objects using URL query parameters as filters
+func (h *SchoolReportHandler) List(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.QueryParams(r.Context(), r.URL.Query())
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to query school report objects: %w", err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School report objects found.",
+		Data:    reports,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *SchoolReportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Get(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to get school report object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School report object found.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+// Lineage traces a school report object back to the raw document it was
+// parsed from. Query parameters filter and page the other reports of the
+// document.
+func (h *SchoolReportHandler) Lineage(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	lineage, err := h.service.Lineage(r.Context(), id, r.URL.Query())
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to get lineage of school report object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School report lineage found.",
+		Data:    lineage,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+// Update replaces the school report object on PUT and merges the body into it on PATCH
+func (h *SchoolReportHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+	if r.Body == nil {
+		common.WriteBadRequestResponse(w, fmt.Errorf("No body for updating school report object present."))
+		return
+	}
+
+	obj, err := h.service.Update(id, r.Body, r.Method == http.MethodPatch)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to update school report object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School report object updated.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *SchoolReportHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Delete(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to delete school report object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School report object deleted.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+func (h *SchoolReportHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := pathId(r)
+	if err != nil {
+		common.WriteBadRequestResponse(w, err)
+		return
+	}
+
+	obj, err := h.service.Restore(id)
+	if err != nil {
+		common.WriteApiErrorResponse(w, fmt.Errorf("Failed to restore school report object %d: %w", id, err))
+		return
+	}
+
+	respBody := common.ResponseBody{
+		Message: "School report object restored.",
+		Data:    obj,
+	}
+
+	common.WriteOkResponse(w, respBody)
+}
+
+// upsertMode reads the mode query parameter, either insert (the default) or upsert
+func upsertMode(params url.Values) (bool, error) {
+	switch mode := params.Get("mode"); mode {
+	case "", "insert":
+		return false, nil
+	case "upsert":
+		return true, nil
+	default:
+		return false, fmt.Errorf("Invalid mode %s, expected insert or upsert.", mode)
+	}
+}
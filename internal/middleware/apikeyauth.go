@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"academic-api/internal/common"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ApiKeyMiddleware struct {
+	keyHeaderName  string
+	authHeaderName string
+	authPrefix     string
+	// Principal names keyed by their API key
+	principals map[string]string
+	// Granted scopes keyed by principal name
+	scopes map[string][]string
+}
+
+// NewApiKeyMiddleware authenticates requests against keys, a map of
+// principal names to their API key as returned by ParseApiKeys. scopes maps
+// principal names to the scopes they are granted.
+func NewApiKeyMiddleware(keyHeaderName string, authHeaderName string, authPrefix string, keys map[string]string, scopes map[string][]string) *ApiKeyMiddleware {
+	principals := make(map[string]string, len(keys))
+	for name, key := range keys {
+		principals[key] = name
+	}
+
+	return &ApiKeyMiddleware{
+		keyHeaderName:  keyHeaderName,
+		authHeaderName: authHeaderName,
+		authPrefix:     authPrefix,
+		principals:     principals,
+		scopes:         scopes,
+	}
+}
+
+// ParseApiKeys parses the API_KEYS setting ("name:key,name:key") into a map
+// of principal names to API keys.
+func ParseApiKeys(setting string) (map[string]string, error) {
+	keys := map[string]string{}
+	seen := map[string]bool{}
+
+	for _, entry := range strings.Split(setting, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, key, found := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		key = strings.TrimSpace(key)
+		if !found || name == "" || key == "" {
+			return nil, fmt.Errorf("Malformed API key entry %q, expected name:key.", entry)
+		}
+		if _, ok := keys[name]; ok {
+			return nil, fmt.Errorf("Duplicate API key name %q.", name)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("API key for %q is already assigned to another name.", name)
+		}
+
+		keys[name] = key
+		seen[key] = true
+	}
+
+	return keys, nil
+}
+
+func (mw *ApiKeyMiddleware) GetMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			key, err := mw.extractAuth(req.Header)
+			if err != nil {
+				logrus.WithError(err).Error("Failed to extract API key.")
+				common.WriteForbiddenResponse(w, err)
+				return
+			}
+
+			ctx, err := mw.authenticate(req.Context(), key)
+			if err != nil {
+				logrus.WithError(err).Error("Failed to verify API key.")
+				common.WriteUnauthorizedResponse(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+func (mw *ApiKeyMiddleware) CheckAuth(key string) (bool, error) {
+	_, err := mw.lookup(key)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LookupPrincipal returns the principal and scopes that key authenticates
+func (mw *ApiKeyMiddleware) LookupPrincipal(key string) (*Principal, error) {
+	name, err := mw.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		Name:   name,
+		Scopes: mw.scopes[name],
+	}, nil
+}
+
+// PrincipalByName returns the principal with the given name and its current
+// scopes, so tokens issued to a principal can be checked against the API key
+// configuration that outlives them
+func (mw *ApiKeyMiddleware) PrincipalByName(name string) (*Principal, error) {
+	for _, principal := range mw.principals {
+		if principal == name {
+			return &Principal{
+				Name:   name,
+				Scopes: mw.scopes[name],
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown principal %q.", name)
+}
+
+// authenticate verifies the key and places its principal on ctx
+func (mw *ApiKeyMiddleware) authenticate(ctx context.Context, key string) (context.Context, error) {
+	principal, err := mw.LookupPrincipal(key)
+	if err != nil {
+		return ctx, err
+	}
+
+	return WithPrincipal(ctx, principal), nil
+}
+
+// lookup returns the principal name of key. Every configured key is compared
+// in constant time so the response time does not leak key prefixes.
+func (mw *ApiKeyMiddleware) lookup(key string) (string, error) {
+	var name string
+	for candidate, principal := range mw.principals {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			name = principal
+		}
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("Invalid API key.")
+	}
+	return name, nil
+}
+
+func (mw *ApiKeyMiddleware) extractAuth(header http.Header) (string, error) {
+	key := strings.TrimSpace(header.Get(mw.keyHeaderName))
+	if key != "" {
+		return key, nil
+	}
+
+	auth := header.Get(mw.authHeaderName)
+	if auth == "" {
+		return "", fmt.Errorf("API key header not present.")
+	}
+	if !strings.HasPrefix(auth, mw.authPrefix+" ") {
+		return "", fmt.Errorf("Malformed auth header.")
+	}
+
+	key = strings.TrimSpace(strings.TrimPrefix(auth, mw.authPrefix))
+	if key == "" {
+		return "", fmt.Errorf("Malformed auth header.")
+	}
+	return key, nil
+}
@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"gotest.tools/v3/assert"
+)
+
+// Test constants
+const (
+	ScraperName string = "scraper"
+	ScraperKey  string = "scraper-key"
+	AdminName   string = "admin"
+	AdminKey    string = "admin-key"
+)
+
+func newTestApiKeyMiddleware() *ApiKeyMiddleware {
+	return NewApiKeyMiddleware(ApiKeyHeaderName, AuthHeaderName, ApiKeyPrefix, map[string]string{
+		ScraperName: ScraperKey,
+		AdminName:   AdminKey,
+	}, map[string][]string{
+		ScraperName: {ScopeReportsRead, ScopeReportsWrite},
+		AdminName:   {ScopeAdmin},
+	})
+}
+
+type ParseApiKeysTestCase struct {
+	name             string
+	setting          string
+	expectedResult   map[string]string
+	expectedErrorMsg string
+}
+
+func TestApiKeyAuth_ParseApiKeys(t *testing.T) {
+	testCases := []ParseApiKeysTestCase{
+		{
+			name:           "Multiple keys",
+			setting:        "scraper:scraper-key, admin:admin-key",
+			expectedResult: map[string]string{ScraperName: ScraperKey, AdminName: AdminKey},
+		},
+		{
+			name:           "Empty setting",
+			setting:        "",
+			expectedResult: map[string]string{},
+		},
+		{
+			name:             "Missing key",
+			setting:          "scraper",
+			expectedErrorMsg: `Malformed API key entry "scraper", expected name:key.`,
+		},
+		{
+			name:             "Duplicate name",
+			setting:          "scraper:one,scraper:two",
+			expectedErrorMsg: `Duplicate API key name "scraper".`,
+		},
+		{
+			name:             "Duplicate key",
+			setting:          "scraper:one,admin:one",
+			expectedErrorMsg: `API key for "admin" is already assigned to another name.`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseApiKeys(tc.setting)
+			if tc.expectedErrorMsg != "" {
+				assert.Error(t, err, tc.expectedErrorMsg)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, resp, tc.expectedResult)
+		})
+	}
+}
+
+type ApiKeyExtractAuthTestCase struct {
+	name             string
+	headers          map[string]string
+	expectedResult   string
+	expectedErrorMsg string
+}
+
+func TestApiKeyAuth_extractAuth(t *testing.T) {
+	testCases := []ApiKeyExtractAuthTestCase{
+		{
+			name:           "API key header",
+			headers:        map[string]string{ApiKeyHeaderName: ScraperKey},
+			expectedResult: ScraperKey,
+		},
+		{
+			name:           "Authorization header",
+			headers:        map[string]string{AuthHeaderName: fmt.Sprintf("%s %s", ApiKeyPrefix, ScraperKey)},
+			expectedResult: ScraperKey,
+		},
+		{
+			name:             "No header",
+			headers:          map[string]string{},
+			expectedErrorMsg: "API key header not present.",
+		},
+		{
+			name:             "Bearer token",
+			headers:          map[string]string{AuthHeaderName: fmt.Sprintf("%s %s", BearerPrefix, ScraperKey)},
+			expectedErrorMsg: "Malformed auth header.",
+		},
+	}
+
+	mw := newTestApiKeyMiddleware()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tc.headers {
+				header.Set(name, value)
+			}
+
+			resp, err := mw.extractAuth(header)
+			if tc.expectedErrorMsg != "" {
+				assert.Error(t, err, tc.expectedErrorMsg)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, resp, tc.expectedResult)
+		})
+	}
+}
+
+func TestApiKeyAuth_CheckAuth(t *testing.T) {
+	testsCases := []CheckAuthTestCase{
+		{
+			name:           "Valid key",
+			token:          AdminKey,
+			expectedResult: true,
+		},
+		{
+			name:             "Invalid key",
+			token:            "not-a-key",
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid API key.",
+		},
+	}
+
+	mw := newTestApiKeyMiddleware()
+
+	for _, tc := range testsCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := mw.CheckAuth(tc.token)
+			if tc.expectedErrorMsg != "" {
+				assert.Error(t, err, tc.expectedErrorMsg)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, resp, tc.expectedResult)
+		})
+	}
+}
+
+type PrincipalByNameTestCase struct {
+	name              string
+	principalName     string
+	expectedPrincipal *Principal
+	expectedErrorMsg  string
+}
+
+func TestApiKeyAuth_PrincipalByName(t *testing.T) {
+	testsCases := []PrincipalByNameTestCase{
+		{
+			name:              "Configured principal",
+			principalName:     ScraperName,
+			expectedPrincipal: &Principal{Name: ScraperName, Scopes: []string{ScopeReportsRead, ScopeReportsWrite}},
+		},
+		{
+			name:             "Removed principal",
+			principalName:    "former",
+			expectedErrorMsg: `Unknown principal "former".`,
+		},
+	}
+
+	mw := newTestApiKeyMiddleware()
+
+	for _, tc := range testsCases {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, err := mw.PrincipalByName(tc.principalName)
+			if tc.expectedErrorMsg != "" {
+				assert.Error(t, err, tc.expectedErrorMsg)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.DeepEqual(t, principal, tc.expectedPrincipal)
+		})
+	}
+}
+
+type ChainAuthTestCase struct {
+	name              string
+	headers           map[string]string
+	expectedStatus    int
+	expectedPrincipal string
+}
+
+func TestChainAuth_GetMiddleware(t *testing.T) {
+	testCases := []ChainAuthTestCase{
+		{
+			name:              "JWT accepted",
+			headers:           map[string]string{AuthHeaderName: fmt.Sprintf("%s %s", BearerPrefix, signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), validClaims()))},
+			expectedStatus:    http.StatusOK,
+			expectedPrincipal: TestSubject,
+		},
+		{
+			name:              "API key header accepted",
+			headers:           map[string]string{ApiKeyHeaderName: ScraperKey},
+			expectedStatus:    http.StatusOK,
+			expectedPrincipal: ScraperName,
+		},
+		{
+			name:              "API key authorization accepted",
+			headers:           map[string]string{AuthHeaderName: fmt.Sprintf("%s %s", ApiKeyPrefix, AdminKey)},
+			expectedStatus:    http.StatusOK,
+			expectedPrincipal: AdminName,
+		},
+		{
+			name:           "Invalid JWT rejected",
+			headers:        map[string]string{AuthHeaderName: fmt.Sprintf("%s %s", BearerPrefix, ValidToken)},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid API key rejected",
+			headers:        map[string]string{ApiKeyHeaderName: "not-a-key"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "No credentials rejected",
+			headers:        map[string]string{},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	mw := NewChainAuthMiddleware(
+		NewJwtMiddleware(AuthHeaderName, BearerPrefix, JwtConfig{Secret: []byte(TestSecret)}),
+		newTestApiKeyMiddleware(),
+	)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.HandleFunc(TestUrl, func(w http.ResponseWriter, r *http.Request) {
+				principal, ok := PrincipalFromContext(r.Context())
+				assert.Assert(t, ok)
+				assert.Equal(t, principal.Name, tc.expectedPrincipal)
+			}).Methods(http.MethodGet)
+			router.Use(mw.GetMiddleware())
+
+			req, _ := http.NewRequest(http.MethodGet, TestUrl, nil)
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, recorder.Code, tc.expectedStatus)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"academic-api/internal/common"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ChainAuthMiddleware accepts a request if any of its auth middlewares does.
+// The first middleware whose credentials are present in the request decides
+// the outcome, so a bad JWT is not retried as an API key.
+type ChainAuthMiddleware struct {
+	auths []IAuthMiddleware
+}
+
+func NewChainAuthMiddleware(auths ...IAuthMiddleware) *ChainAuthMiddleware {
+	return &ChainAuthMiddleware{auths: auths}
+}
+
+func (mw *ChainAuthMiddleware) GetMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			for _, auth := range mw.auths {
+				token, err := auth.extractAuth(req.Header)
+				if err != nil {
+					continue
+				}
+
+				ctx, err := auth.authenticate(req.Context(), token)
+				if err != nil {
+					logrus.WithError(err).Error("Failed to verify auth credentials.")
+					common.WriteUnauthorizedResponse(w, err)
+					return
+				}
+
+				next.ServeHTTP(w, req.WithContext(ctx))
+				return
+			}
+
+			err := fmt.Errorf("Auth header not present.")
+			logrus.WithError(err).Error("Failed to extract auth credentials.")
+			common.WriteForbiddenResponse(w, err)
+		})
+	}
+}
+
+func (mw *ChainAuthMiddleware) CheckAuth(token string) (bool, error) {
+	var err error
+	for _, auth := range mw.auths {
+		var valid bool
+		valid, err = auth.CheckAuth(token)
+		if valid {
+			return true, nil
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("No auth middleware configured.")
+	}
+	return false, err
+}
+
+func (mw *ChainAuthMiddleware) authenticate(ctx context.Context, token string) (context.Context, error) {
+	var err error
+	for _, auth := range mw.auths {
+		var authCtx context.Context
+		authCtx, err = auth.authenticate(ctx, token)
+		if err == nil {
+			return authCtx, nil
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("No auth middleware configured.")
+	}
+	return ctx, err
+}
+
+func (mw *ChainAuthMiddleware) extractAuth(header http.Header) (string, error) {
+	for _, auth := range mw.auths {
+		token, err := auth.extractAuth(header)
+		if err == nil {
+			return token, nil
+		}
+	}
+	return "", fmt.Errorf("Auth header not present.")
+}
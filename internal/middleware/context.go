@@ -0,0 +1,38 @@
+package middleware
+
+import "context"
+
+type contextKey int
+
+const (
+	claimsContextKey contextKey = iota
+	principalContextKey
+)
+
+// Principal identifies the authenticated caller of a request
+type Principal struct {
+	Name   string
+	Scopes []string
+}
+
+// WithClaims returns a copy of ctx carrying the verified JWT claims
+func WithClaims(ctx context.Context, claims *JwtClaims) context.Context {
+	return context.WithValue(ctx, claimsContextKey, claims)
+}
+
+// ClaimsFromContext returns the verified JWT claims of the caller, if any
+func ClaimsFromContext(ctx context.Context) (*JwtClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(*JwtClaims)
+	return claims, ok
+}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal of the caller, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey).(*Principal)
+	return principal, ok
+}
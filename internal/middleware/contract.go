@@ -1,7 +1,9 @@
 package middleware
 
 const (
-	AuthHeaderName = "Authorization"
-	BasicPrefix    = "Basic"
-	BearerPrefix   = "Bearer"
+	AuthHeaderName   = "Authorization"
+	ApiKeyHeaderName = "X-API-Key"
+	BasicPrefix      = "Basic"
+	BearerPrefix     = "Bearer"
+	ApiKeyPrefix     = "ApiKey"
 )
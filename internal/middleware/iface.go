@@ -1,6 +1,9 @@
 package middleware
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 type IMiddlware interface {
 	GetMiddleware() func(http.Handler) http.Handler
@@ -10,4 +13,5 @@ type IAuthMiddleware interface {
 	IMiddlware
 	CheckAuth(string) (bool, error)
 	extractAuth(http.Header) (string, error)
+	authenticate(ctx context.Context, token string) (context.Context, error)
 }
@@ -2,27 +2,90 @@ package middleware
 
 import (
 	"academic-api/internal/common"
+	"context"
+	"crypto/rsa"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	minTokenLen int = 5
+	// Allowed clock drift between the token issuer and this server
+	ClockSkewLeeway = 30 * time.Second
 )
 
+// IRevocationList is consulted for every verified token
+type IRevocationList interface {
+	IsRevoked(jti string) (bool, error)
+}
+
+// JwtConfig holds the keys and expected claims used to verify tokens. At
+// least one of Secret (HS256) or PublicKey (RS256) must be set.
+type JwtConfig struct {
+	Secret      []byte
+	PublicKey   *rsa.PublicKey
+	Issuer      string
+	Audience    string
+	Revocations IRevocationList
+}
+
+// JwtClaims are the verified claims of a bearer token
+type JwtClaims struct {
+	jwt.RegisteredClaims
+	// Space separated list of granted scopes
+	Scope string `json:"scope,omitempty"`
+	// Either TokenUseAccess or TokenUseRefresh, access when empty
+	TokenUse string `json:"token_use,omitempty"`
+}
+
 type JwtMiddleware struct {
 	tokenHeaderName string
 	tokenPrefix     string
+	config          JwtConfig
+	parser          *jwt.Parser
 }
 
-func NewJwtMiddleware(tokenHeaderName string, tokenPrefix string) *JwtMiddleware {
+func NewJwtMiddleware(tokenHeaderName string, tokenPrefix string, config JwtConfig) *JwtMiddleware {
+	var methods []string
+	if len(config.Secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if config.PublicKey != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(ClockSkewLeeway),
+	}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(config.Audience))
+	}
+
 	return &JwtMiddleware{
 		tokenHeaderName: tokenHeaderName,
 		tokenPrefix:     tokenPrefix,
+		config:          config,
+		parser:          jwt.NewParser(opts...),
+	}
+}
+
+// LoadRsaPublicKey reads a PEM encoded RSA public key used to verify RS256 tokens
+func LoadRsaPublicKey(path string) (*rsa.PublicKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
+	return jwt.ParseRSAPublicKeyFromPEM(pemBytes)
 }
 
 func (mw *JwtMiddleware) GetMiddleware() func(http.Handler) http.Handler {
@@ -35,31 +98,103 @@ func (mw *JwtMiddleware) GetMiddleware() func(http.Handler) http.Handler {
 				return
 			}
 
-			tokenValid, err := mw.CheckAuth(token)
+			ctx, err := mw.authenticate(req.Context(), token)
 			if err != nil {
-				logrus.WithError(err).Error("Failed to parse JWT.")
-				common.WriteUnauthorizedResponse(w, err)
-				return
-			}
-			if !tokenValid {
-				logrus.Error("Login attempted with invalidate JWT.")
+				logrus.WithError(err).Error("Failed to verify JWT.")
 				common.WriteUnauthorizedResponse(w, err)
 				return
 			}
 
-			next.ServeHTTP(w, req)
+			next.ServeHTTP(w, req.WithContext(ctx))
 		})
 	}
 }
 
 func (mw *JwtMiddleware) CheckAuth(token string) (bool, error) {
-	// TODO: implement JWT auth. This is just placeholder for now
-	if len(token) < minTokenLen {
-		return false, fmt.Errorf("Token min length %d", minTokenLen)
+	_, err := mw.parseToken(token, TokenUseAccess)
+	if err != nil {
+		return false, err
 	}
 	return true, nil
 }
 
+// authenticate verifies the token and places its claims and principal on ctx
+func (mw *JwtMiddleware) authenticate(ctx context.Context, token string) (context.Context, error) {
+	claims, err := mw.parseToken(token, TokenUseAccess)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx = WithClaims(ctx, claims)
+	return WithPrincipal(ctx, claims.Principal()), nil
+}
+
+// ParseRefreshToken verifies a refresh token issued by JwtIssuer
+func (mw *JwtMiddleware) ParseRefreshToken(token string) (*JwtClaims, error) {
+	return mw.parseToken(token, TokenUseRefresh)
+}
+
+// ParseAnyToken verifies an access or refresh token
+func (mw *JwtMiddleware) ParseAnyToken(token string) (*JwtClaims, error) {
+	return mw.parseToken(token, "")
+}
+
+// parseToken verifies the token signature, registered claims, intended use
+// and revocation status. An empty use accepts any token.
+func (mw *JwtMiddleware) parseToken(token string, use string) (*JwtClaims, error) {
+	if len(mw.config.Secret) == 0 && mw.config.PublicKey == nil {
+		return nil, fmt.Errorf("No JWT verification key configured.")
+	}
+
+	claims := &JwtClaims{}
+	_, err := mw.parser.ParseWithClaims(token, claims, mw.keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid JWT: %w", err)
+	}
+
+	if use != "" && claims.use() != use {
+		return nil, fmt.Errorf("Invalid JWT: %s token used as %s token.", claims.use(), use)
+	}
+
+	if mw.config.Revocations != nil && claims.ID != "" {
+		revoked, err := mw.config.Revocations.IsRevoked(claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check JWT revocation: %w", err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("Invalid JWT: token has been revoked.")
+		}
+	}
+
+	return claims, nil
+}
+
+// Principal returns the caller identified by the claims
+func (c *JwtClaims) Principal() *Principal {
+	return &Principal{
+		Name:   c.Subject,
+		Scopes: strings.Fields(c.Scope),
+	}
+}
+
+func (c *JwtClaims) use() string {
+	if c.TokenUse == "" {
+		return TokenUseAccess
+	}
+	return c.TokenUse
+}
+
+func (mw *JwtMiddleware) keyFunc(token *jwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return mw.config.Secret, nil
+	case *jwt.SigningMethodRSA:
+		return mw.config.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("Unexpected signing method %s.", token.Method.Alg())
+	}
+}
+
 func (mw *JwtMiddleware) extractAuth(header http.Header) (string, error) {
 	token := header.Get(mw.tokenHeaderName)
 	if token == "" {
@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"gotest.tools/v3/assert"
@@ -18,6 +22,10 @@ const (
 	InvalidToken string = "1234"
 	ValidToken   string = "12345"
 	TestUrl      string = "/api/test/"
+	TestSecret   string = "test-secret"
+	TestIssuer   string = "academic-api"
+	TestAudience string = "academic-api-clients"
+	TestSubject  string = "dashboard"
 )
 
 type ExtractAuthTestCase struct {
@@ -52,7 +60,7 @@ func TestJwtAuth_extractAuth(t *testing.T) {
 		},
 	}
 
-	mw := NewJwtMiddleware(AuthHeader, AuthPrefix)
+	mw := NewJwtMiddleware(AuthHeader, AuthPrefix, JwtConfig{Secret: []byte(TestSecret)})
 
 	for _, tc := range testsCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -86,6 +94,25 @@ func TestJwtAuth_extractAuth(t *testing.T) {
 	}
 }
 
+// Builds registered claims that pass verification with the test config
+func validClaims() jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Subject:   TestSubject,
+		Issuer:    TestIssuer,
+		Audience:  jwt.ClaimStrings{TestAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.Claims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	assert.NilError(t, err)
+	return token
+}
+
 type CheckAuthTestCase struct {
 	name             string
 	token            string
@@ -97,22 +124,101 @@ func TestJwtAuth_CheckAuth(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	otherRsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	notYetValid := validClaims()
+	notYetValid.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "someone-else"
+
+	wrongAudience := validClaims()
+	wrongAudience.Audience = jwt.ClaimStrings{"someone-else"}
+
 	testsCases := []CheckAuthTestCase{
 		{
-			name:             "Valid token",
-			token:            ValidToken,
+			name:             "Valid HS256 token",
+			token:            signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), validClaims()),
 			expectedResult:   true,
 			expectedErrorMsg: "",
 		},
 		{
-			name:             "Invalid token",
-			token:            InvalidToken,
+			name:             "Valid RS256 token",
+			token:            signToken(t, jwt.SigningMethodRS256, rsaKey, validClaims()),
+			expectedResult:   true,
+			expectedErrorMsg: "",
+		},
+		{
+			name:             "Malformed token",
+			token:            ValidToken,
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token is malformed: token contains an invalid number of segments",
+		},
+		{
+			name:             "Wrong HS256 secret",
+			token:            signToken(t, jwt.SigningMethodHS256, []byte("wrong-secret"), validClaims()),
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token signature is invalid: signature is invalid",
+		},
+		{
+			name:             "Wrong RS256 key",
+			token:            signToken(t, jwt.SigningMethodRS256, otherRsaKey, validClaims()),
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token signature is invalid: crypto/rsa: verification error",
+		},
+		{
+			name:             "Unsupported signing method",
+			token:            signToken(t, jwt.SigningMethodHS512, []byte(TestSecret), validClaims()),
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token signature is invalid: signing method HS512 is invalid",
+		},
+		{
+			name:             "Expired token",
+			token:            signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), expired),
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token has invalid claims: token is expired",
+		},
+		{
+			name:             "Token not valid yet",
+			token:            signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), notYetValid),
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token has invalid claims: token is not valid yet",
+		},
+		{
+			name:             "Token without expiry",
+			token:            signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), noExpiry),
 			expectedResult:   false,
-			expectedErrorMsg: "Token min length 5",
+			expectedErrorMsg: "Invalid JWT: token has invalid claims: token is missing required claim: exp claim is required",
+		},
+		{
+			name:             "Wrong issuer",
+			token:            signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), wrongIssuer),
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token has invalid claims: token has invalid issuer",
+		},
+		{
+			name:             "Wrong audience",
+			token:            signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), wrongAudience),
+			expectedResult:   false,
+			expectedErrorMsg: "Invalid JWT: token has invalid claims: token has invalid audience",
 		},
 	}
 
-	mw := NewJwtMiddleware(AuthHeader, AuthPrefix)
+	mw := NewJwtMiddleware(AuthHeader, AuthPrefix, JwtConfig{
+		Secret:    []byte(TestSecret),
+		PublicKey: &rsaKey.PublicKey,
+		Issuer:    TestIssuer,
+		Audience:  TestAudience,
+	})
 
 	for _, tc := range testsCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -126,3 +232,62 @@ func TestJwtAuth_CheckAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestJwtAuth_CheckAuthWithoutKey(t *testing.T) {
+	mw := NewJwtMiddleware(AuthHeader, AuthPrefix, JwtConfig{})
+
+	resp, err := mw.CheckAuth(signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), validClaims()))
+	assert.Error(t, err, "No JWT verification key configured.")
+	assert.Equal(t, resp, false)
+}
+
+type GetMiddlewareTestCase struct {
+	name            string
+	header          string
+	expectedStatus  int
+	expectedSubject string
+}
+
+func TestJwtAuth_GetMiddleware(t *testing.T) {
+	testCases := []GetMiddlewareTestCase{
+		{
+			name:            "Claims placed on context",
+			header:          fmt.Sprintf("%s %s", AuthPrefix, signToken(t, jwt.SigningMethodHS256, []byte(TestSecret), validClaims())),
+			expectedStatus:  http.StatusOK,
+			expectedSubject: TestSubject,
+		},
+		{
+			name:           "Invalid token rejected",
+			header:         fmt.Sprintf("%s %s", AuthPrefix, ValidToken),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Missing header rejected",
+			header:         "",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	mw := NewJwtMiddleware(AuthHeader, AuthPrefix, JwtConfig{Secret: []byte(TestSecret)})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.HandleFunc(TestUrl, func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := ClaimsFromContext(r.Context())
+				assert.Assert(t, ok)
+				assert.Equal(t, claims.Subject, tc.expectedSubject)
+			}).Methods(http.MethodGet)
+			router.Use(mw.GetMiddleware())
+
+			req, _ := http.NewRequest(http.MethodGet, TestUrl, nil)
+			if tc.header != "" {
+				req.Header.Add(AuthHeader, tc.header)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, recorder.Code, tc.expectedStatus)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TokenUseAccess  = "access"
+	TokenUseRefresh = "refresh"
+
+	jtiBytes = 16
+)
+
+// JwtIssuer signs HS256 tokens that JwtMiddleware configured with the same
+// secret, issuer and audience accepts.
+type JwtIssuer struct {
+	secret          []byte
+	issuer          string
+	audience        string
+	accessLifetime  time.Duration
+	refreshLifetime time.Duration
+}
+
+func NewJwtIssuer(secret []byte, issuer string, audience string, accessLifetime time.Duration, refreshLifetime time.Duration) *JwtIssuer {
+	return &JwtIssuer{
+		secret:          secret,
+		issuer:          issuer,
+		audience:        audience,
+		accessLifetime:  accessLifetime,
+		refreshLifetime: refreshLifetime,
+	}
+}
+
+// AccessLifetime is how long issued access tokens are valid
+func (i *JwtIssuer) AccessLifetime() time.Duration {
+	return i.accessLifetime
+}
+
+// Issue signs a token of the given use for principal
+func (i *JwtIssuer) Issue(principal *Principal, use string) (string, *JwtClaims, error) {
+	if len(i.secret) == 0 {
+		return "", nil, fmt.Errorf("No JWT signing key configured.")
+	}
+
+	lifetime := i.accessLifetime
+	if use == TokenUseRefresh {
+		lifetime = i.refreshLifetime
+	}
+
+	jti, err := newJti()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	claims := &JwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   principal.Name,
+			Issuer:    i.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+		},
+		Scope:    strings.Join(principal.Scopes, " "),
+		TokenUse: use,
+	}
+	if i.audience != "" {
+		claims.Audience = jwt.ClaimStrings{i.audience}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, claims, nil
+}
+
+func newJti() (string, error) {
+	b := make([]byte, jtiBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type fakeRevocationList struct {
+	revoked map[string]bool
+}
+
+func (f *fakeRevocationList) IsRevoked(jti string) (bool, error) {
+	return f.revoked[jti], nil
+}
+
+func TestJwtIssuer_Issue(t *testing.T) {
+	revocations := &fakeRevocationList{revoked: map[string]bool{}}
+	issuer := NewJwtIssuer([]byte(TestSecret), TestIssuer, TestAudience, time.Hour, 24*time.Hour)
+	mw := NewJwtMiddleware(AuthHeader, AuthPrefix, JwtConfig{
+		Secret:      []byte(TestSecret),
+		Issuer:      TestIssuer,
+		Audience:    TestAudience,
+		Revocations: revocations,
+	})
+	principal := &Principal{Name: ScraperName, Scopes: []string{ScopeReportsRead, ScopeReportsWrite}}
+
+	accessToken, accessClaims, err := issuer.Issue(principal, TokenUseAccess)
+	assert.NilError(t, err)
+	refreshToken, refreshClaims, err := issuer.Issue(principal, TokenUseRefresh)
+	assert.NilError(t, err)
+	assert.Assert(t, accessClaims.ID != refreshClaims.ID)
+	assert.Assert(t, refreshClaims.ExpiresAt.After(accessClaims.ExpiresAt.Time))
+
+	t.Run("Access token accepted", func(t *testing.T) {
+		valid, err := mw.CheckAuth(accessToken)
+		assert.NilError(t, err)
+		assert.Equal(t, valid, true)
+	})
+
+	t.Run("Refresh token rejected for access", func(t *testing.T) {
+		valid, err := mw.CheckAuth(refreshToken)
+		assert.Error(t, err, "Invalid JWT: refresh token used as access token.")
+		assert.Equal(t, valid, false)
+	})
+
+	t.Run("Refresh token parsed with principal", func(t *testing.T) {
+		claims, err := mw.ParseRefreshToken(refreshToken)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, claims.Principal(), principal)
+	})
+
+	t.Run("Revoked token rejected", func(t *testing.T) {
+		revocations.revoked[accessClaims.ID] = true
+		valid, err := mw.CheckAuth(accessToken)
+		assert.Error(t, err, "Invalid JWT: token has been revoked.")
+		assert.Equal(t, valid, false)
+	})
+}
+
+func TestJwtIssuer_IssueWithoutSecret(t *testing.T) {
+	issuer := NewJwtIssuer(nil, TestIssuer, TestAudience, time.Hour, 24*time.Hour)
+
+	_, _, err := issuer.Issue(&Principal{Name: ScraperName}, TokenUseAccess)
+	assert.Error(t, err, "No JWT signing key configured.")
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"academic-api/internal/common"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Read schools and school reports
+	ScopeReportsRead = "reports:read"
+	// Create and modify schools and school reports
+	ScopeReportsWrite = "reports:write"
+	// Grants every other scope
+	ScopeAdmin = "admin"
+)
+
+// HasScope reports whether the principal was granted scope, either directly
+// or through the admin scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// HasScope reports whether the authenticated principal on ctx was granted scope
+func HasScope(ctx context.Context, scope string) bool {
+	principal, ok := PrincipalFromContext(ctx)
+	return ok && principal.HasScope(scope)
+}
+
+// ParseApiKeyScopes parses the API_KEY_SCOPES setting
+// ("name=scope scope,name=scope") into a map of principal names to scopes.
+func ParseApiKeyScopes(setting string) (map[string][]string, error) {
+	scopes := map[string][]string{}
+
+	for _, entry := range strings.Split(setting, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, granted, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("Malformed API key scope entry %q, expected name=scope.", entry)
+		}
+		if _, ok := scopes[name]; ok {
+			return nil, fmt.Errorf("Duplicate API key scope name %q.", name)
+		}
+
+		scopes[name] = strings.Fields(granted)
+	}
+
+	return scopes, nil
+}
+
+// ScopeMiddleware rejects requests whose principal lacks the required scope.
+// It must run after an IAuthMiddleware has placed the principal on the context.
+type ScopeMiddleware struct {
+	scope string
+}
+
+func NewScopeMiddleware(scope string) *ScopeMiddleware {
+	return &ScopeMiddleware{scope: scope}
+}
+
+func (mw *ScopeMiddleware) GetMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if !HasScope(req.Context(), mw.scope) {
+				err := fmt.Errorf("Missing required scope %s.", mw.scope)
+				logrus.WithError(err).Error("Request denied.")
+				common.WriteForbiddenResponse(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"gotest.tools/v3/assert"
+)
+
+type ParseApiKeyScopesTestCase struct {
+	name             string
+	setting          string
+	expectedResult   map[string][]string
+	expectedErrorMsg string
+}
+
+func TestScope_ParseApiKeyScopes(t *testing.T) {
+	testCases := []ParseApiKeyScopesTestCase{
+		{
+			name:    "Multiple principals",
+			setting: "scraper=reports:read reports:write, admin=admin",
+			expectedResult: map[string][]string{
+				ScraperName: {ScopeReportsRead, ScopeReportsWrite},
+				AdminName:   {ScopeAdmin},
+			},
+		},
+		{
+			name:           "Principal without scopes",
+			setting:        "dashboard=",
+			expectedResult: map[string][]string{"dashboard": {}},
+		},
+		{
+			name:             "Missing separator",
+			setting:          "scraper",
+			expectedErrorMsg: `Malformed API key scope entry "scraper", expected name=scope.`,
+		},
+		{
+			name:             "Duplicate name",
+			setting:          "scraper=admin,scraper=reports:read",
+			expectedErrorMsg: `Duplicate API key scope name "scraper".`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := ParseApiKeyScopes(tc.setting)
+			if tc.expectedErrorMsg != "" {
+				assert.Error(t, err, tc.expectedErrorMsg)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, resp, tc.expectedResult)
+		})
+	}
+}
+
+type ScopeMiddlewareTestCase struct {
+	name           string
+	principal      *Principal
+	requiredScope  string
+	expectedStatus int
+}
+
+func TestScope_GetMiddleware(t *testing.T) {
+	testCases := []ScopeMiddlewareTestCase{
+		{
+			name:           "Scope granted",
+			principal:      &Principal{Name: ScraperName, Scopes: []string{ScopeReportsRead, ScopeReportsWrite}},
+			requiredScope:  ScopeReportsWrite,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Admin grants every scope",
+			principal:      &Principal{Name: AdminName, Scopes: []string{ScopeAdmin}},
+			requiredScope:  ScopeReportsWrite,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Read only principal cannot write",
+			principal:      &Principal{Name: "dashboard", Scopes: []string{ScopeReportsRead}},
+			requiredScope:  ScopeReportsWrite,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "No principal",
+			principal:      nil,
+			requiredScope:  ScopeReportsRead,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Handle(TestUrl, NewScopeMiddleware(tc.requiredScope).GetMiddleware()(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			)).Methods(http.MethodGet)
+
+			req, _ := http.NewRequest(http.MethodGet, TestUrl, nil)
+			if tc.principal != nil {
+				req = req.WithContext(WithPrincipal(req.Context(), tc.principal))
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, recorder.Code, tc.expectedStatus)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"academic-api/internal/common"
+	"academic-api/internal/domain"
+	"academic-api/internal/middleware"
+	"context"
+	"errors"
+)
+
+// ErrForbidden is returned when the caller lacks the scope an operation needs
+var ErrForbidden = common.ErrForbidden.Wrap(errors.New("Operation requires admin scope."))
+
+// checkDeletedAccess only lets admins query soft deleted records
+func checkDeletedAccess(ctx context.Context, req *domain.Request) error {
+	if req.IncludesDeleted() && !middleware.HasScope(ctx, middleware.ScopeAdmin) {
+		return ErrForbidden
+	}
+	return nil
+}
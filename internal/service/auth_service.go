@@ -0,0 +1,138 @@
+package service
+
+import (
+	"academic-api/internal/common"
+	"academic-api/internal/domain/token"
+	"academic-api/internal/middleware"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+type IAuthService interface {
+	IssueToken(reqBody io.ReadCloser) (*token.TokenResponse, error)
+	Refresh(reqBody io.ReadCloser) (*token.TokenResponse, error)
+	Revoke(reqBody io.ReadCloser) error
+}
+
+type AuthService struct {
+	IAuthService
+	issuer      *middleware.JwtIssuer
+	verifier    *middleware.JwtMiddleware
+	apiKeys     *middleware.ApiKeyMiddleware
+	revocations IRevocationService
+}
+
+func NewAuthService(issuer *middleware.JwtIssuer, verifier *middleware.JwtMiddleware, apiKeys *middleware.ApiKeyMiddleware, revocations IRevocationService) *AuthService {
+	return &AuthService{
+		issuer:      issuer,
+		verifier:    verifier,
+		apiKeys:     apiKeys,
+		revocations: revocations,
+	}
+}
+
+// IssueToken exchanges an API key or client credentials for a token pair
+func (s *AuthService) IssueToken(reqBody io.ReadCloser) (*token.TokenResponse, error) {
+	tokenReq := &token.TokenRequest{}
+	err := json.NewDecoder(reqBody).Decode(tokenReq)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, common.ErrDecode.Wrap(err)
+	}
+
+	var principal *middleware.Principal
+	switch tokenReq.GrantType {
+	case token.GrantTypeApiKey:
+		principal, err = s.apiKeys.LookupPrincipal(tokenReq.ApiKey)
+		if err != nil {
+			return nil, common.ErrUnauthorized.Wrap(err)
+		}
+	case token.GrantTypeClientCredentials:
+		principal, err = s.apiKeys.LookupPrincipal(tokenReq.ClientSecret)
+		if err != nil || principal.Name != tokenReq.ClientId {
+			return nil, common.ErrUnauthorized.Wrap(fmt.Errorf("Invalid client credentials."))
+		}
+	default:
+		return nil, common.ErrBadRequest.Wrap(fmt.Errorf("Unsupported grant type %q.", tokenReq.GrantType))
+	}
+
+	return s.issuePair(principal)
+}
+
+// Refresh exchanges a refresh token for a new token pair with the current
+// scopes of its subject. The presented refresh token is revoked so it can
+// only be used once.
+func (s *AuthService) Refresh(reqBody io.ReadCloser) (*token.TokenResponse, error) {
+	tokenReq := &token.TokenRequest{}
+	err := json.NewDecoder(reqBody).Decode(tokenReq)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, common.ErrDecode.Wrap(err)
+	}
+
+	claims, err := s.verifier.ParseRefreshToken(tokenReq.RefreshToken)
+	if err != nil {
+		return nil, common.ErrUnauthorized.Wrap(err)
+	}
+
+	// The new pair carries the scopes the API key has now, a key removed
+	// since the refresh token was issued cannot refresh it
+	principal, err := s.apiKeys.PrincipalByName(claims.Subject)
+	if err != nil {
+		return nil, common.ErrUnauthorized.Wrap(err)
+	}
+
+	// A replayed refresh token loses the race to revoke it
+	err = s.revocations.Consume(claims)
+	if errors.Is(err, token.ErrAlreadyRevoked) {
+		return nil, common.ErrUnauthorized.Wrap(err)
+	}
+	if err != nil {
+		logrus.WithError(err).Error("Failed to revoke refresh token.")
+		return nil, err
+	}
+
+	return s.issuePair(principal)
+}
+
+// Revoke adds an access or refresh token to the revocation list
+func (s *AuthService) Revoke(reqBody io.ReadCloser) error {
+	revokeReq := &token.RevokeRequest{}
+	err := json.NewDecoder(reqBody).Decode(revokeReq)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return common.ErrDecode.Wrap(err)
+	}
+
+	claims, err := s.verifier.ParseAnyToken(revokeReq.Token)
+	if err != nil {
+		return common.ErrUnauthorized.Wrap(err)
+	}
+
+	return s.revocations.Revoke(claims)
+}
+
+func (s *AuthService) issuePair(principal *middleware.Principal) (*token.TokenResponse, error) {
+	accessToken, _, err := s.issuer.Issue(principal, middleware.TokenUseAccess)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to sign access token.")
+		return nil, err
+	}
+
+	refreshToken, _, err := s.issuer.Issue(principal, middleware.TokenUseRefresh)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to sign refresh token.")
+		return nil, err
+	}
+
+	return &token.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    token.TokenTypeBearer,
+		ExpiresIn:    int64(s.issuer.AccessLifetime().Seconds()),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"academic-api/internal/common"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// DefaultBulkChunkSize is the number of rows committed per transaction by
+// best effort bulk requests
+const DefaultBulkChunkSize = 500
+
+var (
+	ErrBulkDisabled    = common.ErrNotImplemented.Wrap(errors.New("Bulk import is disabled."))
+	ErrInvalidBulkBody = common.ErrDecode.Wrap(errors.New("Invalid bulk request body."))
+)
+
+// BulkConfig configures the bulk endpoints
+type BulkConfig struct {
+	Enabled   bool
+	ChunkSize int
+}
+
+// decodeBulk splits a bulk request body into its raw rows. The body is either
+// a JSON array or a stream of newline delimited JSON values (NDJSON). Rows are
+// left undecoded so one malformed row does not reject the others.
+func decodeBulk(reqBody io.Reader) ([]json.RawMessage, error) {
+	reader := bufio.NewReader(reqBody)
+	first, err := peekNonSpace(reader)
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w No rows present.", ErrInvalidBulkBody)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []json.RawMessage
+	decoder := json.NewDecoder(reader)
+
+	if first == '[' {
+		_, err = decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w %s", ErrInvalidBulkBody, err)
+		}
+		for decoder.More() {
+			var row json.RawMessage
+			err = decoder.Decode(&row)
+			if err != nil {
+				return nil, fmt.Errorf("%w Row %d: %s", ErrInvalidBulkBody, len(rows), err)
+			}
+			rows = append(rows, row)
+		}
+		_, err = decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w %s", ErrInvalidBulkBody, err)
+		}
+	} else {
+		for {
+			var row json.RawMessage
+			err = decoder.Decode(&row)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w Row %d: %s", ErrInvalidBulkBody, len(rows), err)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w No rows present.", ErrInvalidBulkBody)
+	}
+	return rows, nil
+}
+
+// peekNonSpace returns the first non whitespace byte of reader without consuming it
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			return b, reader.UnreadByte()
+		}
+	}
+}
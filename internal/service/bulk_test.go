@@ -0,0 +1,168 @@
+package service
+
+import (
+	"academic-api/internal/dbtest"
+	rawdata "academic-api/internal/domain/raw_data"
+	"academic-api/internal/domain/school"
+	schoolreport "academic-api/internal/domain/school_report"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"gotest.tools/v3/assert"
+)
+
+// bulkRow returns a school report row of the test school and raw data
+func bulkRow(gradeLevel string, nTested int, nProficient int) string {
+	return fmt.Sprintf(`{"school_id":1,"data_id":1,"academic_year":2024,"subject":"math","grade_level":%q,"demographic_group":"all","n_tested":%d,"n_proficient":%d}`, gradeLevel, nTested, nProficient)
+}
+
+// newBulkService returns a school report service with bulk import enabled on
+// a database holding the school and raw data of bulkRow
+func newBulkService(t *testing.T) *SchoolReportService {
+	session := dbtest.NewSession(t)
+
+	tx, err := session.Begin()
+	assert.NilError(t, err)
+	defer tx.RollbackUnlessCommitted()
+	assert.NilError(t, school.NewSchool("Lincoln Elementary", "AR", "").Create(tx))
+	assert.NilError(t, rawdata.NewRawData(rawdata.ScopeSchool, "test", rawdata.StructureJson, "{}").Create(tx))
+	assert.NilError(t, tx.Commit())
+
+	return NewSchoolReportService(session, BulkConfig{Enabled: true})
+}
+
+// countReports returns the number of stored school reports
+func countReports(t *testing.T, db dbr.SessionRunner) int {
+	var count int
+	err := db.Select("COUNT(*)").From("school_report").LoadOne(&count)
+	assert.NilError(t, err)
+	return count
+}
+
+type DecodeBulkTestCase struct {
+	name         string
+	body         string
+	expectedRows int
+	expectedErr  string
+}
+
+func TestBulk_DecodeBulk(t *testing.T) {
+	testCases := []DecodeBulkTestCase{
+		{name: "JSON array", body: `[{"a":1}, {"a":2}]`, expectedRows: 2},
+		{name: "NDJSON", body: "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n", expectedRows: 3},
+		{name: "Leading whitespace", body: "\n  [{\"a\":1}]", expectedRows: 1},
+		{name: "Empty body", body: "", expectedErr: "Invalid bulk request body. No rows present."},
+		{name: "Blank body", body: " \n\t", expectedErr: "Invalid bulk request body. No rows present."},
+		{name: "Empty array", body: "[]", expectedErr: "Invalid bulk request body. No rows present."},
+		{name: "Malformed line", body: "{\"a\":1}\n{\"a\":\n", expectedErr: "Invalid bulk request body. Row 1: unexpected EOF"},
+		{name: "Malformed array row", body: `[{"a":1}, {"a" 2}]`, expectedErr: "Invalid bulk request body. Row 1: invalid character '2' after object key"},
+		{name: "Unterminated array", body: `[{"a":1}`, expectedErr: "Invalid bulk request body. Row 1: unexpected end of JSON input"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := decodeBulk(strings.NewReader(tc.body))
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Assert(t, errors.Is(err, ErrInvalidBulkBody))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(rows), tc.expectedRows)
+		})
+	}
+}
+
+type BulkCreateTestCase struct {
+	name             string
+	rows             []string
+	ndjson           bool
+	atomic           bool
+	chunkSize        int
+	expectedStatuses []string
+	expectedStored   int
+}
+
+func TestSchoolReportService_BulkCreate(t *testing.T) {
+	created := schoolreport.BulkStatusCreated
+	failed := schoolreport.BulkStatusFailed
+	rolledBack := schoolreport.BulkStatusRolledBack
+
+	testCases := []BulkCreateTestCase{
+		{
+			name:             "JSON array",
+			rows:             []string{bulkRow("3", 10, 5), bulkRow("4", 10, 6)},
+			expectedStatuses: []string{created, created},
+			expectedStored:   2,
+		},
+		{
+			name:             "NDJSON",
+			rows:             []string{bulkRow("3", 10, 5), bulkRow("4", 10, 6)},
+			ndjson:           true,
+			expectedStatuses: []string{created, created},
+			expectedStored:   2,
+		},
+		{
+			name:             "Best effort reports each failed row",
+			rows:             []string{bulkRow("3", 10, 5), bulkRow("4", 10, 11), `{"school_id":"one"}`, bulkRow("5", 10, 6)},
+			expectedStatuses: []string{created, failed, failed, created},
+			expectedStored:   2,
+		},
+		{
+			name:             "Atomic rolls back on an invalid row",
+			rows:             []string{bulkRow("3", 10, 5), bulkRow("4", 10, 11), bulkRow("5", 10, 6)},
+			atomic:           true,
+			expectedStatuses: []string{rolledBack, failed, rolledBack},
+		},
+		{
+			name:             "Atomic rolls back on a failed insert",
+			rows:             []string{bulkRow("3", 10, 5), bulkRow("4", 10, 6), bulkRow("3", 10, 7)},
+			atomic:           true,
+			expectedStatuses: []string{rolledBack, rolledBack, failed},
+		},
+		{
+			name:             "Chunks commit on their own",
+			rows:             []string{bulkRow("3", 10, 5), bulkRow("4", 10, 6), bulkRow("5", 10, 7), bulkRow("3", 10, 8), bulkRow("6", 10, 9)},
+			chunkSize:        2,
+			expectedStatuses: []string{created, created, created, failed, created},
+			expectedStored:   4,
+		},
+		{
+			name:             "Chunk size of one",
+			rows:             []string{bulkRow("3", 10, 5), bulkRow("3", 10, 6), bulkRow("4", 10, 7)},
+			chunkSize:        1,
+			expectedStatuses: []string{created, failed, created},
+			expectedStored:   2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newBulkService(t)
+
+			body := "[" + strings.Join(tc.rows, ",") + "]"
+			if tc.ndjson {
+				body = strings.Join(tc.rows, "\n") + "\n"
+			}
+
+			resp, err := s.BulkCreate(io.NopCloser(strings.NewReader(body)), tc.atomic, false, tc.chunkSize)
+			assert.NilError(t, err)
+
+			statuses := make([]string, len(resp.Results))
+			for i, result := range resp.Results {
+				assert.Equal(t, result.Index, i)
+				statuses[i] = result.Status
+				assert.Equal(t, result.Id != nil, result.Status == created)
+				assert.Equal(t, result.Error != nil, result.Status == failed)
+			}
+			assert.DeepEqual(t, statuses, tc.expectedStatuses)
+			assert.Equal(t, resp.Total, len(tc.rows))
+			assert.Equal(t, resp.Created, tc.expectedStored)
+			assert.Equal(t, countReports(t, s.DbSession), tc.expectedStored)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"academic-api/internal/common"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"academic-api/internal/domain"
+	"academic-api/internal/domain/district"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IDistrictService interface {
+	initRequest(reqBody io.ReadCloser) (*district.DistrictRequest, *dbr.Tx, error)
+	initWriter(reqBody io.ReadCloser) (*district.District, *dbr.Tx, error)
+	Create(reqBody io.ReadCloser) (*district.District, error)
+	Query(ctx context.Context, reqBody io.ReadCloser) (*district.DistrictResponse, error)
+	QueryParams(ctx context.Context, params url.Values) (*district.DistrictResponse, error)
+	Get(id int) (*district.District, error)
+	Update(id int, reqBody io.ReadCloser, partial bool) (*district.District, error)
+	Delete(id int) (*district.District, error)
+	Restore(id int) (*district.District, error)
+}
+
+type DistrictService struct {
+	IDistrictService
+	DbSession *dbr.Session
+}
+
+func NewDistrictService(session *dbr.Session) *DistrictService {
+	return &DistrictService{
+		DbSession: session,
+	}
+}
+
+func (s *DistrictService) initRequest(reqBody io.ReadCloser) (*district.DistrictRequest, *dbr.Tx, error) {
+	reader := &district.DistrictRequest{}
+	err := json.NewDecoder(reqBody).Decode(reader)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, nil, common.ErrDecode.Wrap(err)
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, nil, err
+	}
+
+	return reader, tx, nil
+}
+
+func (s *DistrictService) initWriter(reqBody io.ReadCloser) (*district.District, *dbr.Tx, error) {
+	districtObj := &district.District{}
+	err := json.NewDecoder(reqBody).Decode(districtObj)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, nil, common.ErrDecode.Wrap(err)
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, nil, err
+	}
+
+	return districtObj, tx, nil
+}
+
+func (s *DistrictService) Create(reqBody io.ReadCloser) (*district.District, error) {
+	districtObj, tx, err := s.initWriter(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = districtObj.Create(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return districtObj, err
+}
+
+func (s *DistrictService) Query(ctx context.Context, reqBody io.ReadCloser) (*district.DistrictResponse, error) {
+	reader, tx, err := s.initRequest(reqBody)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to initialize read transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.runQuery(reader, tx)
+}
+
+// QueryParams runs a query whose filters are given as URL query parameters
+func (s *DistrictService) QueryParams(ctx context.Context, params url.Values) (*district.DistrictResponse, error) {
+	reader := &district.DistrictRequest{}
+	err := reader.ParseQuery(params)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to parse query parameters.")
+		return nil, err
+	}
+
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	return s.runQuery(reader, tx)
+}
+
+// Get returns the district with the given id
+func (s *DistrictService) Get(id int) (*district.District, error) {
+	reader := &district.DistrictRequest{}
+	reader.Id = &id
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	resp, err := s.runQuery(reader, tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return resp.Data[0], nil
+}
+
+// Update replaces the district with the given id by the request body. A
+// partial update only replaces the fields present in the body.
+func (s *DistrictService) Update(id int, reqBody io.ReadCloser, partial bool) (*district.District, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	existing, err := district.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.IsDeleted.Bool {
+		return nil, domain.ErrDeleted
+	}
+
+	model := existing.Model
+	districtObj := &district.District{}
+	if partial {
+		districtObj = existing
+	}
+	err = json.NewDecoder(reqBody).Decode(districtObj)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, common.ErrDecode.Wrap(err)
+	}
+
+	// Record metadata is not writable through the body
+	districtObj.Model = model
+
+	err = districtObj.Update(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return districtObj, nil
+}
+
+// Delete soft deletes the district with the given id
+func (s *DistrictService) Delete(id int) (*district.District, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	districtObj, err := district.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = districtObj.Delete(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return districtObj, nil
+}
+
+// Restore undoes the soft delete of the district with the given id
+func (s *DistrictService) Restore(id int) (*district.District, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	districtObj, err := district.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = districtObj.Restore(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return districtObj, nil
+}
+
+func (s *DistrictService) runQuery(reader *district.DistrictRequest, tx *dbr.Tx) (*district.DistrictResponse, error) {
+	resp, err := reader.Query(tx)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to query districts table.")
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
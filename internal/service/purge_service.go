@@ -0,0 +1,105 @@
+package service
+
+import (
+	"academic-api/internal/domain/district"
+	"academic-api/internal/domain/school"
+	schoolreport "academic-api/internal/domain/school_report"
+	"fmt"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IPurgeService interface {
+	Purge(olderThanDays int) (*PurgeResult, error)
+}
+
+// PurgeResult counts the records permanently deleted by a purge
+type PurgeResult struct {
+	DeletedBefore time.Time `json:"deleted_before"`
+	Districts     int64     `json:"districts"`
+	Schools       int64     `json:"schools"`
+	SchoolReports int64     `json:"school_reports"`
+}
+
+type PurgeService struct {
+	IPurgeService
+	DbSession *dbr.Session
+}
+
+func NewPurgeService(session *dbr.Session) *PurgeService {
+	return &PurgeService{
+		DbSession: session,
+	}
+}
+
+// Purge permanently deletes records soft deleted more than olderThanDays
+// days ago. Purging a school also deletes all of its school reports, purging
+// a district keeps its schools without a district.
+func (s *PurgeService) Purge(olderThanDays int) (*PurgeResult, error) {
+	if olderThanDays < 0 {
+		return nil, fmt.Errorf("Invalid retention days: %d", olderThanDays)
+	}
+
+	result := &PurgeResult{
+		DeletedBefore: time.Now().AddDate(0, 0, -olderThanDays),
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	schoolIds, err := school.DeletedBefore(tx, result.DeletedBefore)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to find schools to purge.")
+		return nil, err
+	}
+
+	dependentReports, err := schoolreport.PurgeForSchools(tx, schoolIds)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to purge school reports of purged schools.")
+		return nil, err
+	}
+
+	deletedReports, err := schoolreport.Purge(tx, result.DeletedBefore)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to purge school reports.")
+		return nil, err
+	}
+	result.SchoolReports = dependentReports + deletedReports
+
+	result.Schools, err = school.Purge(tx, schoolIds)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to purge schools.")
+		return nil, err
+	}
+
+	districtIds, err := district.DeletedBefore(tx, result.DeletedBefore)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to find districts to purge.")
+		return nil, err
+	}
+
+	result.Districts, err = district.Purge(tx, districtIds)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to purge districts.")
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"districts":      result.Districts,
+		"schools":        result.Schools,
+		"school_reports": result.SchoolReports,
+	}).Info("Purged soft deleted records.")
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"academic-api/internal/dbtest"
+	"academic-api/internal/domain/district"
+	rawdata "academic-api/internal/domain/raw_data"
+	"academic-api/internal/domain/school"
+	schoolreport "academic-api/internal/domain/school_report"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"gotest.tools/v3/assert"
+)
+
+// softDelete marks a row as deleted the given number of days ago
+func softDelete(t *testing.T, tx *dbr.Tx, table string, id int, daysAgo int) {
+	_, err := tx.Update(table).
+		Set("is_deleted", true).
+		Set("deleted_at", time.Now().AddDate(0, 0, -daysAgo)).
+		Where("id = ?", id).
+		Exec()
+	assert.NilError(t, err)
+}
+
+// remainingIds returns the ids left in a table
+func remainingIds(t *testing.T, db dbr.SessionRunner, table string) []int {
+	ids := []int{}
+	_, err := db.Select("id").From(table).OrderBy("id").Load(&ids)
+	assert.NilError(t, err)
+	return ids
+}
+
+// newPurgeService returns a purge service on a database of districts, schools
+// and reports soft deleted 40 and 10 days ago:
+//
+//	district 1 deleted 40 days ago, district 2 deleted 10 days ago
+//	school 1 of district 1 deleted 40 days ago, with live reports 1 and 2
+//	school 2 deleted 10 days ago, with live report 3
+//	school 3 of district 1, with report 4 deleted 40 days ago, report 5
+//	deleted 10 days ago and live report 6
+func newPurgeService(t *testing.T) *PurgeService {
+	session := dbtest.NewSession(t)
+	tx, err := session.Begin()
+	assert.NilError(t, err)
+	defer tx.RollbackUnlessCommitted()
+
+	assert.NilError(t, district.NewDistrict("Little Rock", "AR", nil).Create(tx))
+	assert.NilError(t, district.NewDistrict("Conway", "AR", nil).Create(tx))
+	assert.NilError(t, rawdata.NewRawData(rawdata.ScopeSchool, "test", rawdata.StructureJson, "{}").Create(tx))
+
+	for _, name := range []string{"Lincoln Elementary", "Jefferson Middle", "Roosevelt High"} {
+		s := school.NewSchool(name, "AR", "")
+		if name != "Jefferson Middle" {
+			districtId := 1
+			s.DistrictId = &districtId
+		}
+		assert.NilError(t, s.Create(tx))
+	}
+
+	for _, report := range []*schoolreport.SchoolReport{
+		schoolreport.NewSchoolReport(1, 1, 2024, "math", "3", "all", 10, 5),
+		schoolreport.NewSchoolReport(1, 1, 2024, "ela", "3", "all", 10, 5),
+		schoolreport.NewSchoolReport(2, 1, 2024, "math", "3", "all", 10, 5),
+		schoolreport.NewSchoolReport(3, 1, 2024, "math", "3", "all", 10, 5),
+		schoolreport.NewSchoolReport(3, 1, 2024, "math", "4", "all", 10, 5),
+		schoolreport.NewSchoolReport(3, 1, 2024, "math", "5", "all", 10, 5),
+	} {
+		assert.NilError(t, report.Create(tx))
+	}
+
+	softDelete(t, tx, "district", 1, 40)
+	softDelete(t, tx, "district", 2, 10)
+	softDelete(t, tx, "school", 1, 40)
+	softDelete(t, tx, "school", 2, 10)
+	softDelete(t, tx, "school_report", 4, 40)
+	softDelete(t, tx, "school_report", 5, 10)
+	assert.NilError(t, tx.Commit())
+
+	return NewPurgeService(session)
+}
+
+type PurgeTestCase struct {
+	name              string
+	olderThanDays     int
+	expectedResult    PurgeResult
+	expectedDistricts []int
+	expectedSchools   []int
+	expectedReports   []int
+}
+
+func TestPurgeService_Purge(t *testing.T) {
+	testCases := []PurgeTestCase{
+		{
+			name:              "Retention beyond every deletion",
+			olderThanDays:     60,
+			expectedDistricts: []int{1, 2},
+			expectedSchools:   []int{1, 2, 3},
+			expectedReports:   []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:              "Cutoff between deletions",
+			olderThanDays:     30,
+			expectedResult:    PurgeResult{Districts: 1, Schools: 1, SchoolReports: 3},
+			expectedDistricts: []int{2},
+			expectedSchools:   []int{2, 3},
+			expectedReports:   []int{3, 5, 6},
+		},
+		{
+			name:              "Every deletion",
+			olderThanDays:     0,
+			expectedResult:    PurgeResult{Districts: 2, Schools: 2, SchoolReports: 5},
+			expectedDistricts: []int{},
+			expectedSchools:   []int{3},
+			expectedReports:   []int{6},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newPurgeService(t)
+
+			result, err := s.Purge(tc.olderThanDays)
+			assert.NilError(t, err)
+			assert.Equal(t, result.Districts, tc.expectedResult.Districts)
+			assert.Equal(t, result.Schools, tc.expectedResult.Schools)
+			assert.Equal(t, result.SchoolReports, tc.expectedResult.SchoolReports)
+
+			assert.DeepEqual(t, remainingIds(t, s.DbSession, "district"), tc.expectedDistricts)
+			assert.DeepEqual(t, remainingIds(t, s.DbSession, "school"), tc.expectedSchools)
+			assert.DeepEqual(t, remainingIds(t, s.DbSession, "school_report"), tc.expectedReports)
+		})
+	}
+
+	t.Run("Purged district leaves its schools", func(t *testing.T) {
+		s := newPurgeService(t)
+		_, err := s.Purge(30)
+		assert.NilError(t, err)
+
+		remaining, err := school.Load(s.DbSession, 3)
+		assert.NilError(t, err)
+		assert.Assert(t, remaining.DistrictId == nil)
+	})
+}
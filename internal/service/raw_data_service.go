@@ -0,0 +1,167 @@
+package service
+
+import (
+	"academic-api/internal/common"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"academic-api/internal/domain"
+	rawdata "academic-api/internal/domain/raw_data"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IRawDataService interface {
+	initRequest(reqBody io.ReadCloser) (*rawdata.RawDataRequest, *dbr.Tx, error)
+	initWriter(reqBody io.ReadCloser) (*rawdata.RawData, *dbr.Tx, error)
+	Create(reqBody io.ReadCloser) (*rawdata.RawData, error)
+	Query(ctx context.Context, reqBody io.ReadCloser) (*rawdata.RawDataResponse, error)
+	QueryParams(ctx context.Context, params url.Values) (*rawdata.RawDataResponse, error)
+	Get(id int) (*rawdata.RawData, error)
+}
+
+type RawDataService struct {
+	IRawDataService
+	DbSession *dbr.Session
+}
+
+func NewRawDataService(session *dbr.Session) *RawDataService {
+	return &RawDataService{
+		DbSession: session,
+	}
+}
+
+func (s *RawDataService) initRequest(reqBody io.ReadCloser) (*rawdata.RawDataRequest, *dbr.Tx, error) {
+	reader := &rawdata.RawDataRequest{}
+	err := json.NewDecoder(reqBody).Decode(reader)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, nil, common.ErrDecode.Wrap(err)
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, nil, err
+	}
+
+	return reader, tx, nil
+}
+
+func (s *RawDataService) initWriter(reqBody io.ReadCloser) (*rawdata.RawData, *dbr.Tx, error) {
+	rawDataObj := &rawdata.RawData{}
+	err := json.NewDecoder(reqBody).Decode(rawDataObj)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, nil, common.ErrDecode.Wrap(err)
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, nil, err
+	}
+
+	return rawDataObj, tx, nil
+}
+
+func (s *RawDataService) Create(reqBody io.ReadCloser) (*rawdata.RawData, error) {
+	rawDataObj, tx, err := s.initWriter(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = rawDataObj.Create(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return rawDataObj, err
+}
+
+func (s *RawDataService) Query(ctx context.Context, reqBody io.ReadCloser) (*rawdata.RawDataResponse, error) {
+	reader, tx, err := s.initRequest(reqBody)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to initialize read transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.runQuery(reader, tx)
+}
+
+// QueryParams runs a query whose filters are given as URL query parameters
+func (s *RawDataService) QueryParams(ctx context.Context, params url.Values) (*rawdata.RawDataResponse, error) {
+	reader := &rawdata.RawDataRequest{}
+	err := reader.ParseQuery(params)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to parse query parameters.")
+		return nil, err
+	}
+
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	return s.runQuery(reader, tx)
+}
+
+// Get returns the raw data with the given id
+func (s *RawDataService) Get(id int) (*rawdata.RawData, error) {
+	reader := &rawdata.RawDataRequest{}
+	reader.Id = &id
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	resp, err := s.runQuery(reader, tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return resp.Data[0], nil
+}
+
+func (s *RawDataService) runQuery(reader *rawdata.RawDataRequest, tx *dbr.Tx) (*rawdata.RawDataResponse, error) {
+	resp, err := reader.Query(tx)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to query raw_data table.")
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
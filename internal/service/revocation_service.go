@@ -0,0 +1,68 @@
+package service
+
+import (
+	"academic-api/internal/domain/token"
+	"academic-api/internal/middleware"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IRevocationService interface {
+	IsRevoked(jti string) (bool, error)
+	Revoke(claims *middleware.JwtClaims) error
+	Consume(claims *middleware.JwtClaims) error
+}
+
+type RevocationService struct {
+	IRevocationService
+	DbSession *dbr.Session
+}
+
+func NewRevocationService(session *dbr.Session) *RevocationService {
+	return &RevocationService{
+		DbSession: session,
+	}
+}
+
+func (s *RevocationService) IsRevoked(jti string) (bool, error) {
+	return token.IsRevoked(s.DbSession, jti)
+}
+
+// Revoke adds the token to the revocation list, revoking it again is not an
+// error
+func (s *RevocationService) Revoke(claims *middleware.JwtClaims) error {
+	return s.revoke(claims, (*token.RevokedToken).Create)
+}
+
+// Consume revokes a single use token, failing with token.ErrAlreadyRevoked
+// when it was revoked before
+func (s *RevocationService) Consume(claims *middleware.JwtClaims) error {
+	return s.revoke(claims, (*token.RevokedToken).Consume)
+}
+
+func (s *RevocationService) revoke(claims *middleware.JwtClaims, create func(*token.RevokedToken, *dbr.Tx) error) error {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	revoked := token.NewRevokedToken(claims.ID, claims.Subject, claims.ExpiresAt.Time)
+	err = create(revoked, tx)
+	if err != nil {
+		return err
+	}
+
+	// Expired tokens are rejected without consulting the list once they are
+	// past the leeway the parser allows after expiry
+	err = token.DeleteExpired(tx, time.Now().Add(-middleware.ClockSkewLeeway))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to delete expired revoked tokens.")
+		return err
+	}
+
+	return tx.Commit()
+}
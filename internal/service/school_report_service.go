@@ -1,9 +1,14 @@
 package service
 
 import (
+	"academic-api/internal/common"
+	"academic-api/internal/domain"
 	schoolreport "academic-api/internal/domain/school_report"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net/url"
 
 	"github.com/gocraft/dbr/v2"
 	"github.com/sirupsen/logrus"
@@ -13,17 +18,31 @@ type ISchoolReportService interface {
 	initRequest(reqBody io.ReadCloser) (*schoolreport.SchoolReportRequest, *dbr.Tx, error)
 	initWriter(reqBody io.ReadCloser) (*schoolreport.SchoolReport, *dbr.Tx, error)
 	Create(reqBody io.ReadCloser) (*schoolreport.SchoolReport, error)
-	Query(reqBody io.ReadCloser) (*schoolreport.SchoolReportResponse, error)
+	Upsert(reqBody io.ReadCloser) (*schoolreport.SchoolReport, string, error)
+	BulkCreate(reqBody io.ReadCloser, atomic bool, upsert bool, chunkSize int) (*schoolreport.BulkResponse, error)
+	Query(ctx context.Context, reqBody io.ReadCloser) (*schoolreport.SchoolReportResponse, error)
+	QueryParams(ctx context.Context, params url.Values) (*schoolreport.SchoolReportResponse, error)
+	Get(id int) (*schoolreport.SchoolReport, error)
+	Lineage(ctx context.Context, id int, params url.Values) (*schoolreport.Lineage, error)
+	Update(id int, reqBody io.ReadCloser, partial bool) (*schoolreport.SchoolReport, error)
+	Delete(id int) (*schoolreport.SchoolReport, error)
+	Restore(id int) (*schoolreport.SchoolReport, error)
 }
 
 type SchoolReportService struct {
 	ISchoolReportService
 	DbSession *dbr.Session
+	bulk      BulkConfig
 }
 
-func NewSchoolReportService(session *dbr.Session) *SchoolReportService {
+func NewSchoolReportService(session *dbr.Session, bulk BulkConfig) *SchoolReportService {
+	if bulk.ChunkSize <= 0 {
+		bulk.ChunkSize = DefaultBulkChunkSize
+	}
+
 	return &SchoolReportService{
 		DbSession: session,
+		bulk:      bulk,
 	}
 }
 
@@ -32,7 +51,7 @@ func (s *SchoolReportService) initWriter(reqBody io.ReadCloser) (*schoolreport.S
 	err := json.NewDecoder(reqBody).Decode(reportObj)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to decode request body.")
-		return nil, nil, err
+		return nil, nil, common.ErrDecode.Wrap(err)
 	}
 
 	tx, err := s.DbSession.Begin()
@@ -49,7 +68,7 @@ func (s *SchoolReportService) initRequest(reqBody io.ReadCloser) (*schoolreport.
 	err := json.NewDecoder(reqBody).Decode(reader)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to decode request body.")
-		return nil, nil, err
+		return nil, nil, common.ErrDecode.Wrap(err)
 	}
 
 	tx, err := s.DbSession.Begin()
@@ -82,7 +101,154 @@ func (s *SchoolReportService) Create(reqBody io.ReadCloser) (*schoolreport.Schoo
 	return reportObj, err
 }
 
-func (s *SchoolReportService) Query(reqBody io.ReadCloser) (*schoolreport.SchoolReportResponse, error) {
+// Upsert creates the school report of the request body, or updates the counts
+// of the school report with the same natural key
+func (s *SchoolReportService) Upsert(reqBody io.ReadCloser) (*schoolreport.SchoolReport, string, error) {
+	reportObj, tx, err := s.initWriter(reqBody)
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	status, err := reportObj.Upsert(tx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return reportObj, status, nil
+}
+
+// BulkCreate creates every school report of a JSON array or NDJSON body. An
+// atomic request creates all rows in one transaction or none of them. A best
+// effort request commits every chunkSize rows and skips the rows that fail.
+// An upsert request updates rows that already exist instead of failing them.
+func (s *SchoolReportService) BulkCreate(reqBody io.ReadCloser, atomic bool, upsert bool, chunkSize int) (*schoolreport.BulkResponse, error) {
+	if !s.bulk.Enabled {
+		return nil, ErrBulkDisabled
+	}
+	if chunkSize <= 0 {
+		chunkSize = s.bulk.ChunkSize
+	}
+
+	rawRows, err := decodeBulk(reqBody)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode bulk request body.")
+		return nil, err
+	}
+
+	resp := &schoolreport.BulkResponse{
+		Atomic:  atomic,
+		Upsert:  upsert,
+		Results: make([]*schoolreport.BulkRowResult, len(rawRows)),
+	}
+	reports := make([]*schoolreport.SchoolReport, len(rawRows))
+	valid := true
+
+	for i, raw := range rawRows {
+		resp.Results[i] = &schoolreport.BulkRowResult{Index: i}
+
+		reportObj := &schoolreport.SchoolReport{}
+		rowErr := json.Unmarshal(raw, reportObj)
+		if rowErr != nil {
+			resp.Results[i].Fail(fmt.Errorf("Invalid school report: %w", rowErr))
+			valid = false
+			continue
+		}
+
+		rowErr = reportObj.ValidateCreate()
+		if rowErr != nil {
+			resp.Results[i].Fail(rowErr)
+			valid = false
+			continue
+		}
+
+		reports[i] = reportObj
+	}
+
+	if atomic {
+		if valid {
+			err = s.bulkInsert(reports, resp.Results, true, upsert)
+		} else {
+			rollBack(resp.Results)
+		}
+	} else {
+		for start := 0; start < len(reports) && err == nil; start += chunkSize {
+			end := min(start+chunkSize, len(reports))
+			err = s.bulkInsert(reports[start:end], resp.Results[start:end], false, upsert)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Tally()
+	return resp, nil
+}
+
+// bulkInsert creates or upserts reports in one transaction, recording the
+// outcome of each in results. Reports that failed validation are nil and
+// skipped. When atomic, the first failed row rolls back the whole transaction.
+func (s *SchoolReportService) bulkInsert(reports []*schoolreport.SchoolReport, results []*schoolreport.BulkRowResult, atomic bool, upsert bool) error {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	for i, reportObj := range reports {
+		if reportObj == nil {
+			continue
+		}
+
+		status := schoolreport.BulkStatusCreated
+		if upsert {
+			status, err = reportObj.Upsert(tx)
+		} else {
+			err = reportObj.Create(tx)
+		}
+		if err != nil {
+			results[i].Fail(err)
+			if atomic {
+				rollBack(results)
+				return nil
+			}
+			continue
+		}
+
+		results[i].Status = status
+		results[i].Id = &reportObj.Id
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to commit bulk insert.")
+		for _, result := range results {
+			if result.Status != schoolreport.BulkStatusFailed {
+				result.Fail(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rollBack marks every row that has not failed as rolled back
+func rollBack(results []*schoolreport.BulkRowResult) {
+	for _, result := range results {
+		if result.Status != schoolreport.BulkStatusFailed {
+			result.Status = schoolreport.BulkStatusRolledBack
+			result.Id = nil
+		}
+	}
+}
+
+func (s *SchoolReportService) Query(ctx context.Context, reqBody io.ReadCloser) (*schoolreport.SchoolReportResponse, error) {
 	reader, tx, err := s.initRequest(reqBody)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to initialize read transaction.")
@@ -90,9 +256,201 @@ func (s *SchoolReportService) Query(reqBody io.ReadCloser) (*schoolreport.School
 	}
 	defer tx.RollbackUnlessCommitted()
 
-	schools, err := reader.Query(tx)
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.runQuery(reader, tx)
+}
+
+// QueryParams runs a query whose filters are given as URL query parameters
+func (s *SchoolReportService) QueryParams(ctx context.Context, params url.Values) (*schoolreport.SchoolReportResponse, error) {
+	reader := &schoolreport.SchoolReportRequest{}
+	err := reader.ParseQuery(params)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to parse query parameters.")
+		return nil, err
+	}
+
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	return s.runQuery(reader, tx)
+}
+
+// Get returns the school report with the given id
+func (s *SchoolReportService) Get(id int) (*schoolreport.SchoolReport, error) {
+	reader := &schoolreport.SchoolReportRequest{}
+	reader.Id = &id
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	resp, err := s.runQuery(reader, tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return resp.Data[0], nil
+}
+
+// Lineage returns the raw document the school report with the given id was
+// parsed from and the other reports parsed from it, filtered and paged by the
+// URL query parameters
+func (s *SchoolReportService) Lineage(ctx context.Context, id int, params url.Values) (*schoolreport.Lineage, error) {
+	reader := &schoolreport.SchoolReportRequest{}
+	err := reader.ParseQuery(params)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to parse query parameters.")
+		return nil, err
+	}
+
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	lineage, err := reader.Lineage(tx, id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to query school report lineage.")
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return lineage, nil
+}
+
+// Update replaces the school report with the given id by the request body. A
+// partial update only replaces the fields present in the body.
+func (s *SchoolReportService) Update(id int, reqBody io.ReadCloser, partial bool) (*schoolreport.SchoolReport, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	existing, err := schoolreport.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.IsDeleted.Bool {
+		return nil, domain.ErrDeleted
+	}
+
+	model := existing.Model
+	reportObj := &schoolreport.SchoolReport{}
+	if partial {
+		reportObj = existing
+	}
+	err = json.NewDecoder(reqBody).Decode(reportObj)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, common.ErrDecode.Wrap(err)
+	}
+
+	// Record metadata is not writable through the body
+	reportObj.Model = model
+
+	err = reportObj.Update(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return reportObj, nil
+}
+
+// Delete soft deletes the school report with the given id
+func (s *SchoolReportService) Delete(id int) (*schoolreport.SchoolReport, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	reportObj, err := schoolreport.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = reportObj.Delete(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return reportObj, nil
+}
+
+// Restore undoes the soft delete of the school report with the given id
+func (s *SchoolReportService) Restore(id int) (*schoolreport.SchoolReport, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	reportObj, err := schoolreport.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = reportObj.Restore(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return reportObj, nil
+}
+
+func (s *SchoolReportService) runQuery(reader *schoolreport.SchoolReportRequest, tx *dbr.Tx) (*schoolreport.SchoolReportResponse, error) {
+	resp, err := reader.Query(tx)
 	if err != nil {
-		logrus.WithError(err).Error("Failed to query schools table.")
+		logrus.WithError(err).Error("Failed to query school reports table.")
 		return nil, err
 	}
 
@@ -101,5 +459,5 @@ func (s *SchoolReportService) Query(reqBody io.ReadCloser) (*schoolreport.School
 		return nil, err
 	}
 
-	return schools, nil
+	return resp, nil
 }
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"academic-api/internal/common"
+	"context"
 	"encoding/json"
 	"io"
+	"net/url"
 
+	"academic-api/internal/domain"
 	"academic-api/internal/domain/school"
 
 	"github.com/gocraft/dbr/v2"
@@ -14,7 +18,12 @@ type ISchoolService interface {
 	initRequest(reqBody io.ReadCloser) (*school.SchoolRequest, *dbr.Tx, error)
 	initWriter(reqBody io.ReadCloser) (*school.School, *dbr.Tx, error)
 	Create(reqBody io.ReadCloser) (*school.School, error)
-	Query(reqBody io.ReadCloser) (*school.SchoolResponse, error)
+	Query(ctx context.Context, reqBody io.ReadCloser) (*school.SchoolResponse, error)
+	QueryParams(ctx context.Context, params url.Values) (*school.SchoolResponse, error)
+	Get(id int) (*school.School, error)
+	Update(id int, reqBody io.ReadCloser, partial bool) (*school.School, error)
+	Delete(id int) (*school.School, error)
+	Restore(id int) (*school.School, error)
 }
 
 type SchoolService struct {
@@ -33,7 +42,7 @@ func (s *SchoolService) initRequest(reqBody io.ReadCloser) (*school.SchoolReques
 	err := json.NewDecoder(reqBody).Decode(reader)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to decode request body.")
-		return nil, nil, err
+		return nil, nil, common.ErrDecode.Wrap(err)
 	}
 
 	tx, err := s.DbSession.Begin()
@@ -50,7 +59,7 @@ func (s *SchoolService) initWriter(reqBody io.ReadCloser) (*school.School, *dbr.
 	err := json.NewDecoder(reqBody).Decode(schoolObj)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to decode request body.")
-		return nil, nil, err
+		return nil, nil, common.ErrDecode.Wrap(err)
 	}
 
 	tx, err := s.DbSession.Begin()
@@ -82,7 +91,7 @@ func (s *SchoolService) Create(reqBody io.ReadCloser) (*school.School, error) {
 	return schoolObj, err
 }
 
-func (s *SchoolService) Query(reqBody io.ReadCloser) (*school.SchoolResponse, error) {
+func (s *SchoolService) Query(ctx context.Context, reqBody io.ReadCloser) (*school.SchoolResponse, error) {
 	reader, tx, err := s.initRequest(reqBody)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to initialize read transaction.")
@@ -90,7 +99,162 @@ func (s *SchoolService) Query(reqBody io.ReadCloser) (*school.SchoolResponse, er
 	}
 	defer tx.RollbackUnlessCommitted()
 
-	schools, err := reader.Query(tx)
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.runQuery(reader, tx)
+}
+
+// QueryParams runs a query whose filters are given as URL query parameters
+func (s *SchoolService) QueryParams(ctx context.Context, params url.Values) (*school.SchoolResponse, error) {
+	reader := &school.SchoolRequest{}
+	err := reader.ParseQuery(params)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to parse query parameters.")
+		return nil, err
+	}
+
+	err = checkDeletedAccess(ctx, &reader.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	return s.runQuery(reader, tx)
+}
+
+// Get returns the school with the given id
+func (s *SchoolService) Get(id int) (*school.School, error) {
+	reader := &school.SchoolRequest{}
+	reader.Id = &id
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	resp, err := s.runQuery(reader, tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return resp.Data[0], nil
+}
+
+// Update replaces the school with the given id by the request body. A
+// partial update only replaces the fields present in the body.
+func (s *SchoolService) Update(id int, reqBody io.ReadCloser, partial bool) (*school.School, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	existing, err := school.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.IsDeleted.Bool {
+		return nil, domain.ErrDeleted
+	}
+
+	model := existing.Model
+	schoolObj := &school.School{}
+	if partial {
+		schoolObj = existing
+	}
+	err = json.NewDecoder(reqBody).Decode(schoolObj)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to decode request body.")
+		return nil, common.ErrDecode.Wrap(err)
+	}
+
+	// Record metadata is not writable through the body
+	schoolObj.Model = model
+
+	err = schoolObj.Update(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return schoolObj, nil
+}
+
+// Delete soft deletes the school with the given id
+func (s *SchoolService) Delete(id int) (*school.School, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	schoolObj, err := school.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schoolObj.Delete(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return schoolObj, nil
+}
+
+// Restore undoes the soft delete of the school with the given id
+func (s *SchoolService) Restore(id int) (*school.School, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	schoolObj, err := school.Load(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schoolObj.Restore(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return schoolObj, nil
+}
+
+func (s *SchoolService) runQuery(reader *school.SchoolRequest, tx *dbr.Tx) (*school.SchoolResponse, error) {
+	resp, err := reader.Query(tx)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to query schools table.")
 		return nil, err
@@ -101,5 +265,5 @@ func (s *SchoolService) Query(reqBody io.ReadCloser) (*school.SchoolResponse, er
 		return nil, err
 	}
 
-	return schools, nil
+	return resp, nil
 }
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"academic-api/internal/domain"
+	rawdata "academic-api/internal/domain/raw_data"
+	schoolreport "academic-api/internal/domain/school_report"
+	webreader "academic-api/internal/web_reader"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IScraperService interface {
+	Fetch(ctx context.Context, state string, fromYear int, toYear int, leaIds []string) (*ScrapeResult, error)
+	Parse(state string, dataIds []int) ([]*schoolreport.SchoolReport, error)
+	Load(state string, dataIds []int) (*ScrapeResult, error)
+	Run(ctx context.Context, state string, fromYear int, toYear int, leaIds []string) (*ScrapeResult, error)
+}
+
+// ScrapeResult counts the documents and school reports handled by a scrape
+type ScrapeResult struct {
+	// Raw data stored or loaded
+	DataIds []int `json:"data_ids"`
+	// Documents that failed to fetch, parse or load
+	Failed int `json:"failed"`
+	// School reports written, by upsert outcome
+	Reports map[string]int `json:"reports"`
+}
+
+// ScraperService scrapes the school reports of any state whose source is
+// registered with webreader.Register
+type ScraperService struct {
+	IScraperService
+	DbSession *dbr.Session
+	client    webreader.IClient
+}
+
+func NewScraperService(session *dbr.Session, client webreader.IClient) *ScraperService {
+	return &ScraperService{
+		DbSession: session,
+		client:    client,
+	}
+}
+
+// Fetch downloads the report cards of the schools for every academic year
+// from fromYear to toYear and stores them as raw data. Without LEA ids, the
+// schools listed by the state source are fetched.
+func (s *ScraperService) Fetch(ctx context.Context, state string, fromYear int, toYear int, leaIds []string) (*ScrapeResult, error) {
+	return s.scrape(ctx, state, fromYear, toYear, leaIds, false)
+}
+
+// Run fetches the report cards like Fetch and loads the school reports of
+// each one as soon as it is stored
+func (s *ScraperService) Run(ctx context.Context, state string, fromYear int, toYear int, leaIds []string) (*ScrapeResult, error) {
+	return s.scrape(ctx, state, fromYear, toYear, leaIds, true)
+}
+
+func (s *ScraperService) scrape(ctx context.Context, state string, fromYear int, toYear int, leaIds []string, load bool) (*ScrapeResult, error) {
+	source, err := webreader.Source(state, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(leaIds) == 0 {
+		leaIds, err = source.ListSchools(ctx, s.DbSession)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to list schools.")
+			return nil, err
+		}
+		logrus.WithField("schools", len(leaIds)).Info("Listed schools to scrape.")
+	}
+
+	result := &ScrapeResult{Reports: map[string]int{}}
+	for _, leaId := range leaIds {
+		for year := fromYear; year <= toYear; year++ {
+			log := logrus.WithFields(logrus.Fields{
+				"lea_id":        leaId,
+				"academic_year": year,
+			})
+
+			data, stored, err := s.fetch(ctx, source, year, leaId)
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if err != nil {
+				log.WithError(err).Error("Failed to fetch report card.")
+				result.Failed++
+				continue
+			}
+			result.DataIds = append(result.DataIds, data.Id)
+			log = log.WithField("data_id", data.Id)
+			if stored {
+				log.Info("Fetched report card.")
+			} else {
+				log.Info("Report card unchanged, reusing raw data.")
+			}
+
+			if !load {
+				continue
+			}
+			counts, err := s.load(source, data.Id)
+			if err != nil {
+				log.WithError(err).Error("Failed to load report card.")
+				result.Failed++
+				continue
+			}
+			addCounts(result.Reports, counts)
+			log.WithField("reports", counts).Info("Loaded report card.")
+		}
+	}
+
+	return result, nil
+}
+
+// fetch downloads a report card and stores it as raw data. A report card
+// whose content hash matches the last raw data of its source is not stored
+// again, that raw data is returned with stored unset.
+func (s *ScraperService) fetch(ctx context.Context, source webreader.StateSource, academicYear int, leaId string) (data *rawdata.RawData, stored bool, err error) {
+	data, err = source.FetchReport(ctx, academicYear, leaId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, false, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	latest, err := rawdata.LoadLatest(tx, data.Source)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, false, err
+	}
+	if latest != nil {
+		unchanged, err := sameContent(data, latest)
+		if err != nil {
+			return nil, false, err
+		}
+		if unchanged {
+			return latest, false, nil
+		}
+	}
+
+	err = data.Create(tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, tx.Commit()
+}
+
+// sameContent reports whether two raw data have the same content hash
+func sameContent(a *rawdata.RawData, b *rawdata.RawData) (bool, error) {
+	hashA, err := a.Hash()
+	if err != nil {
+		return false, err
+	}
+	hashB, err := b.Hash()
+	if err != nil {
+		return false, err
+	}
+	return hashA == hashB, nil
+}
+
+// Parse returns the school reports parsed from stored raw data without
+// writing them. Their school is not resolved.
+func (s *ScraperService) Parse(state string, dataIds []int) ([]*schoolreport.SchoolReport, error) {
+	source, err := webreader.Source(state, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	var reports []*schoolreport.SchoolReport
+	for _, id := range dataIds {
+		data, err := rawdata.Load(tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load raw data %d: %w", id, err)
+		}
+
+		parsed, err := source.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse raw data %d: %w", id, err)
+		}
+		for _, report := range parsed {
+			report.DataId = data.Id
+		}
+		reports = append(reports, parsed...)
+	}
+
+	return reports, nil
+}
+
+// Load parses stored raw data and upserts the school reports of each
+func (s *ScraperService) Load(state string, dataIds []int) (*ScrapeResult, error) {
+	source, err := webreader.Source(state, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScrapeResult{Reports: map[string]int{}}
+	for _, id := range dataIds {
+		log := logrus.WithField("data_id", id)
+
+		counts, err := s.load(source, id)
+		if err != nil {
+			log.WithError(err).Error("Failed to load report card.")
+			result.Failed++
+			continue
+		}
+		result.DataIds = append(result.DataIds, id)
+		addCounts(result.Reports, counts)
+		log.WithField("reports", counts).Info("Loaded report card.")
+	}
+
+	return result, nil
+}
+
+// load parses the raw data with the given id and upserts its school reports
+// in one transaction
+func (s *ScraperService) load(source webreader.StateSource, dataId int) (map[string]int, error) {
+	tx, err := s.DbSession.Begin()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create database transaction.")
+		return nil, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	data, err := rawdata.Load(tx, dataId)
+	if err != nil {
+		return nil, err
+	}
+	if data.IsDeleted.Bool {
+		return nil, domain.ErrDeleted
+	}
+
+	reports, err := source.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := webreader.NewWriter(source).WriteReports(tx, data, reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, tx.Commit()
+}
+
+// addCounts adds the report counts of a document to the totals
+func addCounts(totals map[string]int, counts map[string]int) {
+	for status, count := range counts {
+		totals[status] += count
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"academic-api/internal/dbtest"
+	rawdata "academic-api/internal/domain/raw_data"
+	webreader "academic-api/internal/web_reader"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// fakeSource serves the report card content of each LEA id
+type fakeSource struct {
+	webreader.StateSource
+	content map[string]string
+}
+
+func (s *fakeSource) FetchReport(ctx context.Context, academicYear int, leaId string) (*rawdata.RawData, error) {
+	return rawdata.NewRawData(rawdata.ScopeSchool, "https://example.test/src?lea="+leaId, rawdata.StructureJson, s.content[leaId]), nil
+}
+
+type FetchTestCase struct {
+	name           string
+	leaId          string
+	content        string
+	expectedId     int
+	expectedStored bool
+}
+
+func TestScraperService_Fetch(t *testing.T) {
+	service := NewScraperService(dbtest.NewSession(t), nil)
+	source := &fakeSource{content: map[string]string{}}
+
+	// Cases run in order against the same database
+	testCases := []FetchTestCase{
+		{name: "First fetch", leaId: "1", content: `{"n": 1}`, expectedId: 1, expectedStored: true},
+		{name: "Unchanged", leaId: "1", content: `{"n": 1}`, expectedId: 1, expectedStored: false},
+		{name: "Another school", leaId: "2", content: `{"n": 1}`, expectedId: 2, expectedStored: true},
+		{name: "Changed", leaId: "1", content: `{"n": 2}`, expectedId: 3, expectedStored: true},
+		{name: "Unchanged since the change", leaId: "1", content: `{"n": 2}`, expectedId: 3, expectedStored: false},
+		{name: "Back to earlier content", leaId: "1", content: `{"n": 1}`, expectedId: 4, expectedStored: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source.content[tc.leaId] = tc.content
+
+			data, stored, err := service.fetch(context.Background(), source, 2024, tc.leaId)
+			assert.NilError(t, err)
+			assert.Equal(t, data.Id, tc.expectedId)
+			assert.Equal(t, stored, tc.expectedStored)
+		})
+	}
+
+	t.Run("Deleted raw data is not reused", func(t *testing.T) {
+		tx, err := service.DbSession.Begin()
+		assert.NilError(t, err)
+		data, err := rawdata.Load(tx, 4)
+		assert.NilError(t, err)
+		assert.NilError(t, data.Delete(tx))
+		assert.NilError(t, tx.Commit())
+
+		data, stored, err := service.fetch(context.Background(), source, 2024, "1")
+		assert.NilError(t, err)
+		assert.Equal(t, data.Id, 5)
+		assert.Equal(t, stored, true)
+	})
+
+	var count int
+	assert.NilError(t, service.DbSession.Select("COUNT(*)").From("raw_data").LoadOne(&count))
+	assert.Equal(t, count, 5)
+}
@@ -12,9 +12,66 @@ fy: Fiscal Year (seems like xy -> 20xy-11 -- 20xy-10)
 */
 
 const (
+	// StateCode is the state the package reads school reports of
+	StateCode = "AR"
+
 	base_url string = "https://myschoolinfo.arkansas.gov/StandardReports/SRC?"
 )
 
+// Labels of the report card, lowercased with spaces collapsed, by the value
+// they map to. Rows with other labels are not reported.
 var (
-	demographic_groups = []string{"all"}
+	subjects = map[string]string{
+		"ela":                   "ela",
+		"english language arts": "ela",
+		"literacy":              "ela",
+		"math":                  "math",
+		"mathematics":           "math",
+	}
+
+	grade_levels = map[string]string{
+		"3":          "3",
+		"4":          "4",
+		"5":          "5",
+		"6":          "6",
+		"7":          "7",
+		"8":          "8",
+		"3-8":        "3-8",
+		"grades 3-8": "3-8",
+		// Aggregates of every tested grade, which run to grade 10 in ATLAS
+		// and ACT Aspire results, so they are not labeled 3-8
+		"all grades": "all",
+	}
+
+	demographic_groups = map[string]string{
+		"all":                        "all",
+		"all students":               "all",
+		"black":                      "black",
+		"african american":           "black",
+		"black/african american":     "black",
+		"hispanic":                   "hispanic",
+		"hispanic/latino":            "hispanic",
+		"economically disadvantaged": "economically_disadvantaged",
+	}
+)
+
+// Header labels of the columns of the assessment proficiency table, by column
+var header_labels = map[string][]string{
+	column_grade:      {"grade", "grade level"},
+	column_subject:    {"subject", "content area"},
+	column_group:      {"student group", "demographic group", "subgroup", "group"},
+	column_tested:     {"# tested", "n tested", "number tested", "tested"},
+	column_proficient: {"# proficient", "n proficient", "number proficient", "proficient"},
+}
+
+// Columns of the assessment proficiency table
+const (
+	column_grade      = "grade"
+	column_subject    = "subject"
+	column_group      = "student group"
+	column_tested     = "# tested"
+	column_proficient = "# proficient"
 )
+
+// Rows searched for the header of the assessment proficiency table
+const max_header_row = 20
@@ -1,11 +1,31 @@
 package arkansas
 
 import (
+	rawdata "academic-api/internal/domain/raw_data"
+	schoolreport "academic-api/internal/domain/school_report"
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
 )
 
+// ErrLayout is wrapped by every error about a workbook that does not have the
+// expected report card layout
+var ErrLayout = errors.New("Report card layout is not recognized.")
+
+// Count cells of groups too small to be reported
+var suppressed_counts = []string{"", "*", "-", "n<10", "n/a", "rv"}
+
 type IParser interface {
 	buildUrl(academicYear int, schoolId string) (string, error)
+	parseUrl(source string) (int, string, error)
+	Parse(data *rawdata.RawData) ([]*schoolreport.SchoolReport, error)
 }
 
 type Parser struct {
@@ -19,5 +39,203 @@ func NewParser() *Parser {
 func (p *Parser) buildUrl(academicYear int, schoolId string) (string, error) {
 	fy := strconv.Itoa(academicYear - 2000 + 10)
 
-	return base_url + "lea=" + schoolId + "&fy=" + fy + "&format=Excel", nil
+	query := url.Values{
+		"lea":    {schoolId},
+		"fy":     {fy},
+		"format": {"Excel"},
+	}
+	return base_url + query.Encode(), nil
+}
+
+// parseUrl returns the academic year and school id of a report card url built
+// by buildUrl
+func (p *Parser) parseUrl(source string) (int, string, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return 0, "", fmt.Errorf("Invalid report card url %s: %w", source, err)
+	}
+
+	params := u.Query()
+	schoolId := params.Get("lea")
+	fy, err := strconv.Atoi(params.Get("fy"))
+	if schoolId == "" || err != nil {
+		return 0, "", fmt.Errorf("Report card url %s has no lea and fy parameters.", source)
+	}
+
+	return fy + 2000 - 10, schoolId, nil
+}
+
+// Parse returns the ELA and math school reports of a report card workbook,
+// read from the assessment proficiency table. Rows of other subjects, grades
+// or groups and rows with suppressed counts are skipped. The school of the
+// reports is left for the writer to set.
+func (p *Parser) Parse(data *rawdata.RawData) ([]*schoolreport.SchoolReport, error) {
+	academicYear, _, err := p.parseUrl(data.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := data.Content()
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := excelize.OpenReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open report card workbook: %w", err)
+	}
+	defer book.Close()
+
+	table, err := findTable(book)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*schoolreport.SchoolReport
+	seen := map[string]int{}
+	for i := table.header + 1; i < len(table.rows); i++ {
+		report, err := table.parseRow(i)
+		if err != nil {
+			return nil, err
+		}
+		if report == nil {
+			continue
+		}
+
+		key := report.Subject + "/" + report.GradeLevel + "/" + report.DemographicGroup
+		if first, ok := seen[key]; ok {
+			return nil, fmt.Errorf("Sheet %s rows %d and %d both report grade %s %s for %s: %w", table.sheet, first+1, i+1, report.GradeLevel, report.Subject, report.DemographicGroup, ErrLayout)
+		}
+		seen[key] = i
+
+		report.DataId = data.Id
+		report.AcademicYear = academicYear
+		reports = append(reports, report)
+	}
+
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("Sheet %s has no ELA or math proficiency rows: %w", table.sheet, ErrLayout)
+	}
+	return reports, nil
+}
+
+// proficiencyTable is the assessment proficiency table of a workbook
+type proficiencyTable struct {
+	sheet string
+	rows  [][]string
+	// Index of the header row
+	header int
+	// Index of each column
+	columns map[string]int
+}
+
+// findTable returns the first table whose header labels a tested count column
+// among the first rows of a sheet
+func findTable(book *excelize.File) (*proficiencyTable, error) {
+	for _, sheet := range book.GetSheetList() {
+		rows, err := book.GetRows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read sheet %s: %w", sheet, err)
+		}
+
+		for i := range min(len(rows), max_header_row) {
+			columns := headerColumns(rows[i])
+			if _, ok := columns[column_tested]; !ok {
+				continue
+			}
+
+			for _, column := range []string{column_grade, column_subject, column_group, column_proficient} {
+				if _, ok := columns[column]; !ok {
+					return nil, fmt.Errorf("Sheet %s header on row %d has no %s column: %w", sheet, i+1, column, ErrLayout)
+				}
+			}
+			return &proficiencyTable{sheet: sheet, rows: rows, header: i, columns: columns}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No sheet has an assessment proficiency table with a %s column: %w", column_tested, ErrLayout)
+}
+
+// headerColumns returns the index of each column labeled in a row
+func headerColumns(row []string) map[string]int {
+	columns := map[string]int{}
+	for i, cell := range row {
+		label := normalizeLabel(cell)
+		for column, labels := range header_labels {
+			if _, ok := columns[column]; !ok && slices.Contains(labels, label) {
+				columns[column] = i
+			}
+		}
+	}
+	return columns
+}
+
+// parseRow returns the school report of a row, or nil when the row is not
+// reported
+func (t *proficiencyTable) parseRow(i int) (*schoolreport.SchoolReport, error) {
+	subject, ok := subjects[normalizeLabel(t.cell(i, column_subject))]
+	if !ok {
+		return nil, nil
+	}
+	gradeLevel, ok := grade_levels[normalizeGrade(t.cell(i, column_grade))]
+	if !ok {
+		return nil, nil
+	}
+	group, ok := demographic_groups[normalizeLabel(t.cell(i, column_group))]
+	if !ok {
+		return nil, nil
+	}
+
+	tested, ok, err := t.count(i, column_tested)
+	if err != nil || !ok {
+		return nil, err
+	}
+	proficient, ok, err := t.count(i, column_proficient)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if proficient > tested {
+		return nil, fmt.Errorf("Sheet %s row %d has more proficient than tested students: %w", t.sheet, i+1, ErrLayout)
+	}
+
+	return schoolreport.NewSchoolReport(0, 0, 0, subject, gradeLevel, group, tested, proficient), nil
+}
+
+// cell returns the value of a column in a row, empty when the row is short
+func (t *proficiencyTable) cell(i int, column string) string {
+	row := t.rows[i]
+	if j := t.columns[column]; j < len(row) {
+		return row[j]
+	}
+	return ""
+}
+
+// count returns the whole number of a count column in a row, and false when
+// the count is suppressed
+func (t *proficiencyTable) count(i int, column string) (int, bool, error) {
+	value := t.cell(i, column)
+	if slices.Contains(suppressed_counts, normalizeLabel(value)) {
+		return 0, false, nil
+	}
+
+	n, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil || n < 0 || n != math.Trunc(n) {
+		return 0, false, fmt.Errorf("Sheet %s row %d has an invalid %s count %q: %w", t.sheet, i+1, column, value, ErrLayout)
+	}
+	return int(n), true, nil
+}
+
+// normalizeLabel lowercases a label and collapses its whitespace
+func normalizeLabel(label string) string {
+	return strings.Join(strings.Fields(strings.ToLower(label)), " ")
+}
+
+// normalizeGrade normalizes a grade label, dropping a grade prefix and
+// leading zeros so that "Grade 03" reads as "3"
+func normalizeGrade(label string) string {
+	grade := strings.TrimPrefix(normalizeLabel(label), "grade ")
+	if trimmed := strings.TrimLeft(grade, "0"); trimmed != "" {
+		return trimmed
+	}
+	return grade
 }
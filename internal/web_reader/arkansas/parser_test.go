@@ -0,0 +1,167 @@
+package arkansas
+
+import (
+	rawdata "academic-api/internal/domain/raw_data"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const testSource = "https://myschoolinfo.arkansas.gov/StandardReports/SRC?lea=6040704&fy=34&format=Excel"
+
+// testReport is the part of a parsed school report the tests compare
+type testReport struct {
+	Subject     string
+	GradeLevel  string
+	Group       string
+	NTested     int
+	NProficient int
+}
+
+type ParseTestCase struct {
+	name            string
+	fixture         string
+	expectedReports []testReport
+	expectedErr     string
+}
+
+func TestParser_Parse(t *testing.T) {
+	testCases := []ParseTestCase{
+		{
+			name:    "Report card",
+			fixture: "report_card.xlsx",
+			expectedReports: []testReport{
+				{Subject: "ela", GradeLevel: "3", Group: "all", NTested: 52, NProficient: 31},
+				{Subject: "math", GradeLevel: "3", Group: "all", NTested: 52, NProficient: 27},
+				{Subject: "ela", GradeLevel: "3", Group: "black", NTested: 14, NProficient: 6},
+				{Subject: "ela", GradeLevel: "4", Group: "all", NTested: 1048, NProficient: 612},
+				{Subject: "math", GradeLevel: "4", Group: "economically_disadvantaged", NTested: 30, NProficient: 12},
+				{Subject: "math", GradeLevel: "3-8", Group: "all", NTested: 101, NProficient: 39},
+				{Subject: "math", GradeLevel: "all", Group: "all", NTested: 181, NProficient: 79},
+			},
+		},
+		{
+			name:        "Missing column",
+			fixture:     "missing_column.xlsx",
+			expectedErr: "Sheet Assessment header on row 1 has no # proficient column: Report card layout is not recognized.",
+		},
+		{
+			name:        "No proficiency table",
+			fixture:     "no_table.xlsx",
+			expectedErr: "No sheet has an assessment proficiency table with a # tested column: Report card layout is not recognized.",
+		},
+		{
+			name:        "Invalid count",
+			fixture:     "invalid_count.xlsx",
+			expectedErr: `Sheet Assessment row 2 has an invalid # tested count "fifty": Report card layout is not recognized.`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join("testdata", tc.fixture))
+			assert.NilError(t, err)
+
+			data := rawdata.NewRawData(rawdata.ScopeSchool, testSource, rawdata.StructureXlsx, rawdata.EncodeContent(rawdata.StructureXlsx, content))
+			data.Id = 7
+
+			reports, err := NewParser().Parse(data)
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Assert(t, errors.Is(err, ErrLayout))
+				return
+			}
+			assert.NilError(t, err)
+
+			parsed := make([]testReport, len(reports))
+			for i, report := range reports {
+				assert.Equal(t, report.DataId, 7)
+				assert.Equal(t, report.AcademicYear, 2024)
+				parsed[i] = testReport{
+					Subject:     report.Subject,
+					GradeLevel:  report.GradeLevel,
+					Group:       report.DemographicGroup,
+					NTested:     report.NTested,
+					NProficient: report.NProficient,
+				}
+			}
+			assert.DeepEqual(t, parsed, tc.expectedReports)
+		})
+	}
+}
+
+// srcExport is the sidecar of a downloaded SRC export in testdata/src
+type srcExport struct {
+	Source  string       `json:"source"`
+	Reports []testReport `json:"reports"`
+}
+
+func TestParser_ParseSrcExport(t *testing.T) {
+	workbooks, err := filepath.Glob(filepath.Join("testdata", "src", "*.xlsx"))
+	assert.NilError(t, err)
+	if len(workbooks) == 0 {
+		t.Skip("No downloaded SRC export in testdata/src, see testdata/src/README.md.")
+	}
+
+	for _, workbook := range workbooks {
+		t.Run(filepath.Base(workbook), func(t *testing.T) {
+			sidecar, err := os.ReadFile(strings.TrimSuffix(workbook, ".xlsx") + ".json")
+			assert.NilError(t, err)
+			expected := srcExport{}
+			assert.NilError(t, json.Unmarshal(sidecar, &expected))
+
+			content, err := os.ReadFile(workbook)
+			assert.NilError(t, err)
+			data := rawdata.NewRawData(rawdata.ScopeSchool, expected.Source, rawdata.StructureXlsx, rawdata.EncodeContent(rawdata.StructureXlsx, content))
+
+			reports, err := NewParser().Parse(data)
+			assert.NilError(t, err)
+
+			parsed := make([]testReport, len(reports))
+			for i, report := range reports {
+				parsed[i] = testReport{
+					Subject:     report.Subject,
+					GradeLevel:  report.GradeLevel,
+					Group:       report.DemographicGroup,
+					NTested:     report.NTested,
+					NProficient: report.NProficient,
+				}
+			}
+			assert.DeepEqual(t, parsed, expected.Reports)
+		})
+	}
+}
+
+type ParseUrlTestCase struct {
+	name                 string
+	academicYear         int
+	schoolId             string
+	expectedAcademicYear int
+	expectedSchoolId     string
+}
+
+func TestParser_ParseUrl(t *testing.T) {
+	testCases := []ParseUrlTestCase{
+		{name: "2023-24", academicYear: 2024, schoolId: "6040704", expectedAcademicYear: 2024, expectedSchoolId: "6040704"},
+		{name: "2022-23", academicYear: 2023, schoolId: "0101001", expectedAcademicYear: 2023, expectedSchoolId: "0101001"},
+		{name: "Reserved characters", academicYear: 2024, schoolId: "60 407&fy=1#04", expectedAcademicYear: 2024, expectedSchoolId: "60 407&fy=1#04"},
+	}
+
+	p := NewParser()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source, err := p.buildUrl(tc.academicYear, tc.schoolId)
+			assert.NilError(t, err)
+
+			academicYear, schoolId, err := p.parseUrl(source)
+			assert.NilError(t, err)
+			assert.Equal(t, academicYear, tc.expectedAcademicYear)
+			assert.Equal(t, schoolId, tc.expectedSchoolId)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package arkansas
+
+import (
+	"academic-api/internal/domain"
+	rawdata "academic-api/internal/domain/raw_data"
+	webreader "academic-api/internal/web_reader"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type IReader interface {
+	Fetch(ctx context.Context, academicYear int, schoolId string) (*rawdata.RawData, error)
+}
+
+// Reader downloads school report card workbooks from myschoolinfo.arkansas.gov
+type Reader struct {
+	IReader
+	client webreader.IClient
+	parser *Parser
+}
+
+func NewReader(client webreader.IClient) *Reader {
+	return &Reader{
+		client: client,
+		parser: NewParser(),
+	}
+}
+
+// Fetch downloads the report card of a school for an academic year, as raw
+// data that is not stored yet
+func (r *Reader) Fetch(ctx context.Context, academicYear int, schoolId string) (*rawdata.RawData, error) {
+	source, err := r.parser.buildUrl(academicYear, schoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := r.client.Get(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	data := rawdata.NewRawData(rawdata.ScopeSchool, source, rawdata.StructureXlsx, rawdata.EncodeContent(rawdata.StructureXlsx, content))
+	data.FetchedAt = domain.NullTime{
+		NullTime: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	return data, nil
+}
@@ -0,0 +1,53 @@
+package arkansas
+
+import (
+	rawdata "academic-api/internal/domain/raw_data"
+	"academic-api/internal/domain/school"
+	schoolreport "academic-api/internal/domain/school_report"
+	webreader "academic-api/internal/web_reader"
+	"context"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+func init() {
+	webreader.Register(StateCode, NewSource)
+}
+
+// Source reads the school report cards of myschoolinfo.arkansas.gov
+type Source struct {
+	webreader.StateSource
+	reader *Reader
+	parser *Parser
+}
+
+func NewSource(client webreader.IClient) webreader.StateSource {
+	return &Source{
+		reader: NewReader(client),
+		parser: NewParser(),
+	}
+}
+
+func (s *Source) StateCode() string {
+	return StateCode
+}
+
+// ListSchools returns the LEA ids set on Arkansas schools, the state has no
+// school directory to list them from
+func (s *Source) ListSchools(ctx context.Context, db dbr.SessionRunner) ([]string, error) {
+	return school.LeaIds(db, StateCode)
+}
+
+func (s *Source) FetchReport(ctx context.Context, academicYear int, leaId string) (*rawdata.RawData, error) {
+	return s.reader.Fetch(ctx, academicYear, leaId)
+}
+
+// LeaId returns the LEA id of the report card url of data
+func (s *Source) LeaId(data *rawdata.RawData) (string, error) {
+	_, leaId, err := s.parser.parseUrl(data.Source)
+	return leaId, err
+}
+
+func (s *Source) Parse(data *rawdata.RawData) ([]*schoolreport.SchoolReport, error) {
+	return s.parser.Parse(data)
+}
@@ -0,0 +1,98 @@
+//go:build ignore
+
+// Generates the report card fixture workbooks of the parser tests. Run from
+// this directory with go run generate.go.
+package main
+
+import (
+	"log"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var header = []any{"Grade", "Subject", "Student Group", "# Tested", "# Proficient", "% Proficient"}
+
+// Title rows above the proficiency table, as on the published report card
+var titles = [][]any{
+	{"School Report Card 2023-2024"},
+	{"Lincoln Elementary School (6040704)"},
+	{},
+}
+
+var proficiency = [][]any{
+	{"Grade 03", "ELA", "All Students", 52, 31, 59.62},
+	{"Grade 03", "Mathematics", "All Students", 52, 27, 51.92},
+	{"Grade 03", "ELA", "Black/African American", 14, 6, 42.86},
+	{"Grade 03", "ELA", "Hispanic/Latino", "n<10", "n<10", "n<10"},
+	{"Grade 04", "English Language Arts", "All Students", "1,048", "612", 58.4},
+	{"Grade 04", "Science", "All Students", 49, 20, 40.82},
+	{"Grade 04", "Math", "Economically Disadvantaged", 30, 12, 40},
+	{"Grade 04", "Math", "Two or More Races", 11, 5, 45.45},
+	{"Grade 09", "ELA", "All Students", 80, 40, 50},
+	{"Grades 3-8", "Math", "All Students", 101, 39, 38.61},
+	{"All Grades", "Math", "All Students", 181, 79, 43.65},
+	{},
+	{"n<10: fewer than ten students tested, counts are suppressed"},
+}
+
+func main() {
+	write("report_card.xlsx", func(book *excelize.File) {
+		addRows(book, "Overview", [][]any{{"School", "Lincoln Elementary School"}, {"LEA", "6040704"}})
+		addRows(book, "Assessment", append(append(titles, header), proficiency...))
+	})
+
+	write("missing_column.xlsx", func(book *excelize.File) {
+		addRows(book, "Assessment", [][]any{
+			{"Grade", "Subject", "Student Group", "# Tested", "% Proficient"},
+			{"Grade 03", "ELA", "All Students", 52, 59.62},
+		})
+	})
+
+	write("no_table.xlsx", func(book *excelize.File) {
+		addRows(book, "Overview", [][]any{{"School", "Lincoln Elementary School"}})
+	})
+
+	write("invalid_count.xlsx", func(book *excelize.File) {
+		addRows(book, "Assessment", [][]any{
+			header,
+			{"Grade 03", "ELA", "All Students", "fifty", 31, 59.62},
+		})
+	})
+}
+
+// write saves a workbook built by build, without the default sheet
+func write(name string, build func(book *excelize.File)) {
+	book := excelize.NewFile()
+	defer book.Close()
+
+	build(book)
+	err := book.DeleteSheet("Sheet1")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = book.SaveAs(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// addRows adds a sheet holding rows
+func addRows(book *excelize.File, sheet string, rows [][]any) {
+	_, err := book.NewSheet(sheet)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = book.SetSheetRow(sheet, cell, &row)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package webreader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Client defaults, matching the SCRAPER_* settings of .env.example
+const (
+	DefaultTimeout       = 60 * time.Second
+	DefaultRetryAttempts = 3
+	DefaultUserAgent     = "Academic-Data-Collector/1.0"
+)
+
+// Delay before the first retry, doubled for every following retry
+const defaultRetryDelay = time.Second
+
+type IClient interface {
+	Get(ctx context.Context, url string) ([]byte, error)
+}
+
+// ClientConfig configures the requests of a Client
+type ClientConfig struct {
+	// Timeout of each attempt
+	Timeout time.Duration
+	// Retries after a failed attempt, on network errors, 429 and 5xx responses
+	RetryAttempts int
+	UserAgent     string
+}
+
+// Client fetches documents from state publications over HTTP
+type Client struct {
+	IClient
+	http       *http.Client
+	config     ClientConfig
+	retryDelay time.Duration
+}
+
+func NewClient(config ClientConfig) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.RetryAttempts < 0 {
+		config.RetryAttempts = 0
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+
+	return &Client{
+		http:       &http.Client{Timeout: config.Timeout},
+		config:     config,
+		retryDelay: defaultRetryDelay,
+	}
+}
+
+// Get returns the body of url, retrying transient failures with exponential
+// backoff
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	delay := c.retryDelay
+	for attempt := 0; ; attempt++ {
+		body, retry, err := c.get(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		if !retry || attempt >= c.config.RetryAttempts {
+			return nil, err
+		}
+
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"url":     url,
+			"attempt": attempt + 1,
+		}).Warn("Failed to fetch document, retrying.")
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// get makes a single attempt, reporting whether a failure may be retried
+func (c *Client) get(ctx context.Context, url string) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("Failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return nil, retry, fmt.Errorf("Failed to fetch %s: unexpected status %s.", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("Failed to read %s: %w", url, err)
+	}
+	return body, false, nil
+}
@@ -0,0 +1,53 @@
+package webreader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type ClientGetTestCase struct {
+	name             string
+	statuses         []int
+	retryAttempts    int
+	expectedAttempts int
+	expectedErr      bool
+}
+
+func TestClient_Get(t *testing.T) {
+	testCases := []ClientGetTestCase{
+		{name: "Success", statuses: []int{200}, retryAttempts: 3, expectedAttempts: 1},
+		{name: "Retries server errors", statuses: []int{503, 500, 200}, retryAttempts: 3, expectedAttempts: 3},
+		{name: "Retries rate limits", statuses: []int{429, 200}, retryAttempts: 3, expectedAttempts: 2},
+		{name: "Gives up after retry attempts", statuses: []int{503, 503, 503}, retryAttempts: 1, expectedAttempts: 2, expectedErr: true},
+		{name: "Does not retry client errors", statuses: []int{404, 200}, retryAttempts: 3, expectedAttempts: 1, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.UserAgent(), "test-agent")
+				w.WriteHeader(tc.statuses[min(attempts, len(tc.statuses)-1)])
+				attempts++
+				w.Write([]byte("report"))
+			}))
+			defer server.Close()
+
+			client := NewClient(ClientConfig{RetryAttempts: tc.retryAttempts, UserAgent: "test-agent"})
+			client.retryDelay = 0
+
+			body, err := client.Get(context.Background(), server.URL)
+			assert.Equal(t, attempts, tc.expectedAttempts)
+			if tc.expectedErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, string(body), "report")
+		})
+	}
+}
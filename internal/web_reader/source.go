@@ -0,0 +1,76 @@
+package webreader
+
+import (
+	rawdata "academic-api/internal/domain/raw_data"
+	schoolreport "academic-api/internal/domain/school_report"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// StateSource reads the school reports a state publishes
+type StateSource interface {
+	// StateCode is the code of the state, as stored on schools
+	StateCode() string
+	// ListSchools returns the LEA ids of the schools to collect reports of
+	ListSchools(ctx context.Context, db dbr.SessionRunner) ([]string, error)
+	// FetchReport downloads the report of a school for an academic year, as
+	// raw data that is not stored yet
+	FetchReport(ctx context.Context, academicYear int, leaId string) (*rawdata.RawData, error)
+	// LeaId returns the LEA id of the school a report was fetched for
+	LeaId(data *rawdata.RawData) (string, error)
+	// Parse returns the school reports of raw data. The school of the reports
+	// is left for WriteReports to set.
+	Parse(data *rawdata.RawData) ([]*schoolreport.SchoolReport, error)
+}
+
+// SourceFactory returns the source of a state fetching through client
+type SourceFactory func(client IClient) StateSource
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = map[string]SourceFactory{}
+)
+
+// Register makes the source of a state available by its state code. It is
+// meant to be called from the init function of the state package and panics
+// when the state is registered twice.
+func Register(stateCode string, factory SourceFactory) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	stateCode = strings.ToUpper(stateCode)
+	if _, ok := sources[stateCode]; ok {
+		panic("webreader: Register called twice for state " + stateCode)
+	}
+	sources[stateCode] = factory
+}
+
+// Source returns the registered source of a state fetching through client
+func Source(stateCode string, client IClient) (StateSource, error) {
+	sourcesMu.RLock()
+	factory, ok := sources[strings.ToUpper(stateCode)]
+	sourcesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("Unsupported state %s, expected one of %s.", stateCode, strings.Join(States(), ", "))
+	}
+	return factory(client), nil
+}
+
+// States returns the codes of the registered states, sorted
+func States() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+
+	states := make([]string, 0, len(sources))
+	for stateCode := range sources {
+		states = append(states, stateCode)
+	}
+	slices.Sort(states)
+	return states
+}
@@ -0,0 +1,58 @@
+package webreader
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// testSource is a state source that reads nothing
+type testSource struct {
+	StateSource
+	stateCode string
+}
+
+func (s *testSource) StateCode() string {
+	return s.stateCode
+}
+
+type SourceTestCase struct {
+	name              string
+	stateCode         string
+	expectedStateCode string
+	expectedErr       string
+}
+
+func TestSource_Source(t *testing.T) {
+	Register("zz", func(client IClient) StateSource { return &testSource{stateCode: "ZZ"} })
+	Register("ZY", func(client IClient) StateSource { return &testSource{stateCode: "ZY"} })
+	defer func() {
+		delete(sources, "ZZ")
+		delete(sources, "ZY")
+	}()
+
+	testCases := []SourceTestCase{
+		{name: "Registered", stateCode: "ZZ", expectedStateCode: "ZZ"},
+		{name: "Ignores case", stateCode: "zy", expectedStateCode: "ZY"},
+		{name: "Unsupported", stateCode: "ZX", expectedErr: "Unsupported state ZX, expected one of ZY, ZZ."},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source, err := Source(tc.stateCode, nil)
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, source.StateCode(), tc.expectedStateCode)
+		})
+	}
+
+	t.Run("Duplicate", func(t *testing.T) {
+		defer func() {
+			assert.Assert(t, recover() != nil)
+		}()
+		Register("ZZ", func(client IClient) StateSource { return &testSource{stateCode: "ZZ"} })
+	})
+}
@@ -0,0 +1,62 @@
+package webreader
+
+import (
+	"academic-api/internal/domain"
+	rawdata "academic-api/internal/domain/raw_data"
+	"academic-api/internal/domain/school"
+	schoolreport "academic-api/internal/domain/school_report"
+	"errors"
+	"fmt"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+type IWriter interface {
+	WriteReports(db *dbr.Tx, data *rawdata.RawData, reports []*schoolreport.SchoolReport) (map[string]int, error)
+}
+
+// Writer stores the school reports parsed from the raw data of a state source
+type Writer struct {
+	IWriter
+	source StateSource
+}
+
+func NewWriter(source StateSource) *Writer {
+	return &Writer{source: source}
+}
+
+// WriteReports upserts the reports parsed from data into the school the
+// report was fetched for, which must have its lea_id set. It returns the
+// number of reports by upsert outcome.
+func (w *Writer) WriteReports(db *dbr.Tx, data *rawdata.RawData, reports []*schoolreport.SchoolReport) (map[string]int, error) {
+	stateCode := w.source.StateCode()
+	leaId, err := w.source.LeaId(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := school.LoadByLea(db, stateCode, leaId)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("No school with lea_id %s in %s: %w", leaId, stateCode, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.IsDeleted.Bool {
+		return nil, fmt.Errorf("School %d with lea_id %s: %w", s.Id, leaId, domain.ErrDeleted)
+	}
+
+	counts := map[string]int{}
+	for _, report := range reports {
+		report.SchoolId = s.Id
+		report.DataId = data.Id
+
+		status, err := report.Upsert(db)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to write %d %s grade %s %s report: %w", report.AcademicYear, report.Subject, report.GradeLevel, report.DemographicGroup, err)
+		}
+		counts[status]++
+	}
+
+	return counts, nil
+}
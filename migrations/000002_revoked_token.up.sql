@@ -0,0 +1,11 @@
+-- ============================================================================
+-- REVOKED TOKEN TABLE
+-- ============================================================================
+-- JWT ids (jti) that must no longer be accepted. Rows can be removed once
+-- expires_at has passed since the token would be rejected anyway.
+CREATE TABLE revoked_token (
+    jti TEXT PRIMARY KEY,
+    subject TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
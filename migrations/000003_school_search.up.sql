@@ -0,0 +1,32 @@
+-- ============================================================================
+-- SCHOOL SEARCH INDEX
+-- ============================================================================
+-- Full-text index over school and district names, kept in sync with the
+-- school table by triggers. The API must be built with the sqlite_fts5 tag
+-- for go-sqlite3 to write to it.
+CREATE VIRTUAL TABLE school_search USING fts5(
+    school_name,
+    district_name,
+    content = 'school',
+    content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+CREATE TRIGGER school_search_insert
+AFTER INSERT ON school BEGIN
+INSERT INTO school_search (rowid, school_name, district_name)
+VALUES (new.id, new.school_name, new.district_name);
+END;
+CREATE TRIGGER school_search_delete
+AFTER DELETE ON school BEGIN
+INSERT INTO school_search (school_search, rowid, school_name, district_name)
+VALUES ('delete', old.id, old.school_name, old.district_name);
+END;
+CREATE TRIGGER school_search_update
+AFTER UPDATE OF school_name, district_name ON school BEGIN
+INSERT INTO school_search (school_search, rowid, school_name, district_name)
+VALUES ('delete', old.id, old.school_name, old.district_name);
+INSERT INTO school_search (rowid, school_name, district_name)
+VALUES (new.id, new.school_name, new.district_name);
+END;
+-- Index the schools created before this migration
+INSERT INTO school_search (school_search) VALUES ('rebuild');
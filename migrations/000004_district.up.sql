@@ -0,0 +1,51 @@
+-- ============================================================================
+-- DISTRICT TABLE
+-- ============================================================================
+CREATE TABLE district (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    district_name TEXT NOT NULL,
+    state_code TEXT NOT NULL,
+    -- Local education agency id assigned by the state or NCES
+    lea_id TEXT,
+    is_deleted BOOLEAN,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    UNIQUE (state_code, lea_id)
+);
+CREATE UNIQUE INDEX district_state_name ON district (state_code, district_name COLLATE NOCASE);
+-- ============================================================================
+-- SCHOOL DISTRICT REFERENCE
+-- ============================================================================
+-- school.district_name is kept as a copy of the district name so existing
+-- filters and the school search index keep working
+ALTER TABLE school ADD COLUMN district_id INTEGER REFERENCES district(id);
+CREATE INDEX school_district_id ON school (district_id);
+-- One district per distinct district name in each state, ignoring case and
+-- surrounding whitespace
+INSERT INTO district (district_name, state_code, is_deleted, created_at, updated_at)
+SELECT MIN(TRIM(district_name)),
+    state_code,
+    FALSE,
+    CURRENT_TIMESTAMP,
+    CURRENT_TIMESTAMP
+FROM school
+WHERE TRIM(district_name) <> ''
+GROUP BY state_code,
+    LOWER(TRIM(district_name));
+UPDATE school
+SET district_id = (
+        SELECT district.id
+        FROM district
+        WHERE district.state_code = school.state_code
+            AND district.district_name = TRIM(school.district_name) COLLATE NOCASE
+    ),
+    district_name = COALESCE(
+        (
+            SELECT district.district_name
+            FROM district
+            WHERE district.state_code = school.state_code
+                AND district.district_name = TRIM(school.district_name) COLLATE NOCASE
+        ),
+        district_name
+    );
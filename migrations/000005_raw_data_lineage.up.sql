@@ -0,0 +1,15 @@
+-- ============================================================================
+-- RAW DATA LINEAGE
+-- ============================================================================
+-- When the document was fetched from its source, and the hex SHA-256 of its
+-- content so a report can be traced back to the exact publication
+ALTER TABLE raw_data ADD COLUMN fetched_at DATETIME;
+ALTER TABLE raw_data ADD COLUMN content_hash TEXT;
+-- Documents stored before fetch times were recorded were fetched when stored.
+-- SQLite cannot hash content, so the hash of existing documents stays NULL
+-- until they are updated and lineage computes it from the content meanwhile.
+UPDATE raw_data
+SET fetched_at = created_at
+WHERE fetched_at IS NULL;
+-- Reports derived from the same raw document
+CREATE INDEX school_report_data_id ON school_report (data_id);
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- SCHOOL LEA ID
+-- ============================================================================
+-- Local education agency id the state publishes school reports under, used
+-- by the scraper to find the school of a report
+ALTER TABLE school ADD COLUMN lea_id TEXT;
+CREATE UNIQUE INDEX school_state_lea_id ON school (state_code, lea_id);
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- RAW DATA SOURCE
+-- ============================================================================
+-- The scraper looks up the latest document of a source to skip storing it
+-- again when its content has not changed
+CREATE INDEX raw_data_source ON raw_data (source, id);
@@ -4,9 +4,28 @@
 -- ============================================================================
 -- DROP EXISTING TABLES (for clean reinstall)
 -- ============================================================================
+DROP TABLE IF EXISTS school_search;
+DROP TABLE IF EXISTS revoked_token;
 DROP TABLE IF EXISTS raw_data;
 DROP TABLE IF EXISTS school_report;
 DROP TABLE IF EXISTS school;
+DROP TABLE IF EXISTS district;
+-- ============================================================================
+-- DISTRICT TABLE
+-- ============================================================================
+CREATE TABLE district (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    district_name TEXT NOT NULL,
+    state_code TEXT NOT NULL,
+    -- Local education agency id assigned by the state or NCES
+    lea_id TEXT,
+    is_deleted BOOLEAN,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    UNIQUE (state_code, lea_id)
+);
+CREATE UNIQUE INDEX district_state_name ON district (state_code, district_name COLLATE NOCASE);
 -- ============================================================================
 -- SCHOOLS TABLE
 -- ============================================================================
@@ -14,12 +33,18 @@ CREATE TABLE school (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     school_name TEXT NOT NULL,
     state_code TEXT NOT NULL,
+    district_id INTEGER REFERENCES district(id),
+    -- Copy of the district name, see district_id
     district_name TEXT NOT NULL,
+    -- Local education agency id assigned by the state
+    lea_id TEXT,
     is_deleted BOOLEAN,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME,
     deleted_at DATETIME
 );
+CREATE INDEX school_district_id ON school (district_id);
+CREATE UNIQUE INDEX school_state_lea_id ON school (state_code, lea_id);
 -- ============================================================================
 -- RAW DATA TABLE
 -- ============================================================================
@@ -44,11 +69,16 @@ CREATE TABLE raw_data (
         )
     ),
     actual TEXT NOT NULL,
+    -- When the document was fetched from its source
+    fetched_at DATETIME,
+    -- Hex SHA-256 of the decoded content
+    content_hash TEXT,
     is_deleted BOOLEAN,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME,
     deleted_at DATETIME
 );
+CREATE INDEX raw_data_source ON raw_data (source, id);
 -- ============================================================================
 -- SINGLE SCHOOL DATA TABLE (Main Fact Table)
 -- ============================================================================
@@ -94,3 +124,43 @@ CREATE TABLE school_report (
         OR n_proficient <= n_tested
     )
 );
+CREATE INDEX school_report_data_id ON school_report (data_id);
+-- ============================================================================
+-- REVOKED TOKEN TABLE
+-- ============================================================================
+CREATE TABLE revoked_token (
+    jti TEXT PRIMARY KEY,
+    subject TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+-- ============================================================================
+-- SCHOOL SEARCH INDEX
+-- ============================================================================
+-- Full-text index over school and district names, kept in sync with the
+-- school table by triggers. The API must be built with the sqlite_fts5 tag
+-- for go-sqlite3 to write to it.
+CREATE VIRTUAL TABLE school_search USING fts5(
+    school_name,
+    district_name,
+    content = 'school',
+    content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+CREATE TRIGGER school_search_insert
+AFTER INSERT ON school BEGIN
+INSERT INTO school_search (rowid, school_name, district_name)
+VALUES (new.id, new.school_name, new.district_name);
+END;
+CREATE TRIGGER school_search_delete
+AFTER DELETE ON school BEGIN
+INSERT INTO school_search (school_search, rowid, school_name, district_name)
+VALUES ('delete', old.id, old.school_name, old.district_name);
+END;
+CREATE TRIGGER school_search_update
+AFTER UPDATE OF school_name, district_name ON school BEGIN
+INSERT INTO school_search (school_search, rowid, school_name, district_name)
+VALUES ('delete', old.id, old.school_name, old.district_name);
+INSERT INTO school_search (rowid, school_name, district_name)
+VALUES (new.id, new.school_name, new.district_name);
+END;
@@ -3,10 +3,12 @@
 # Default values
 NUM_ENTRIES=5
 API_URL="http://127.0.0.1:8080"
-AUTH_TOKEN="12345"
+API_VERSION="v1"
+AUTH_TOKEN=""
+API_KEY=""
 
 # Parse command line arguments
-while getopts "n:u:t:h" opt; do
+while getopts "n:u:t:k:h" opt; do
   case $opt in
     n)
       NUM_ENTRIES=$OPTARG
@@ -17,11 +19,15 @@ while getopts "n:u:t:h" opt; do
     t)
       AUTH_TOKEN=$OPTARG
       ;;
+    k)
+      API_KEY=$OPTARG
+      ;;
     h)
-      echo "Usage: $0 [-n num_entries] [-u api_url] [-t auth_token]"
+      echo "Usage: $0 [-n num_entries] [-u api_url] [-t auth_token | -k api_key]"
       echo "  -n: Number of entries to create for each type (default: 10)"
       echo "  -u: API base URL (default: http://127.0.0.1:8080)"
-      echo "  -t: Authorization token (default: 12345)"
+      echo "  -t: JWT used as bearer token"
+      echo "  -k: API key exchanged for a JWT at /auth/token"
       exit 0
       ;;
     \?)
@@ -31,6 +37,23 @@ while getopts "n:u:t:h" opt; do
   esac
 done
 
+# Exchange the API key for a JWT
+if [ -z "$AUTH_TOKEN" ]; then
+  if [ -z "$API_KEY" ]; then
+    echo "Either -t auth_token or -k api_key is required" >&2
+    exit 1
+  fi
+
+  TOKEN_RESPONSE=$(curl -s -X POST "$API_URL/auth/token" \
+    -d "{\"grant_type\": \"api_key\", \"api_key\": \"$API_KEY\"}")
+  AUTH_TOKEN=$(echo $TOKEN_RESPONSE | grep -o '"access_token":"[^"]*"' | cut -d'"' -f4)
+
+  if [ -z "$AUTH_TOKEN" ]; then
+    echo "Failed to obtain token: $TOKEN_RESPONSE" >&2
+    exit 1
+  fi
+fi
+
 # Arrays for random data generation
 STATE_CODES=("MA" "TX" "AZ")
 SCHOOL_NAMES=("Lincoln Elementary" "Washington High" "Jefferson Middle" "Roosevelt Academy" "Kennedy School" "Madison Institute" "Monroe Education Center" "Adams Learning Academy" "Jackson Preparatory" "Wilson Charter")
@@ -73,12 +96,25 @@ echo ""
 echo "Created ${#SCHOOL_IDS[@]} schools"
 echo ""
 
+# Create the raw document the school reports are parsed from
+RESPONSE=$(curl -s -X POST "$API_URL/$API_VERSION/raw-data" \
+  -H "Authorization: Bearer $AUTH_TOKEN" \
+  -d "{\"scope\": \"state\", \"source\": \"generate_test_data.sh\", \"structure\": \"json\", \"actual\": \"{}\"}")
+DATA_ID=$(echo $RESPONSE | grep -o '"id":[0-9]*' | grep -o '[0-9]*' | head -1)
+
+if [ -z "$DATA_ID" ]; then
+  echo "Failed to create raw data: $RESPONSE" >&2
+  exit 1
+fi
+
+echo "Created raw data (ID: $DATA_ID)"
+echo ""
+
 # Create School Reports
 echo "Creating School Reports..."
 for i in $(seq 1 $NUM_ENTRIES); do
   # Use random school ID from created schools
   SCHOOL_ID=${SCHOOL_IDS[$RANDOM % ${#SCHOOL_IDS[@]}]}
-  DATA_ID=$((RANDOM % 100 + 1))
   ACADEMIC_YEAR=$((2020 + RANDOM % 5))
   SUBJECT=${SUBJECTS[$RANDOM % ${#SUBJECTS[@]}]}
   GRADE=${GRADE_LEVELS[$RANDOM % ${#GRADE_LEVELS[@]}]}